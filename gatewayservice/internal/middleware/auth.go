@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+)
+
+// Authenticate validates the bearer token of every request that carries one and puts the
+// resolved user on the request context, from where it is forwarded to the backends as a
+// signed identity. Requests without an Authorization header pass through untouched so
+// public routes such as login keep working.
+func Authenticate(mux *runtime.ServeMux, keys *auth.KeySet, userClient auth.UserClient, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		user, err := auth.Authenticate(ctx, keys, userClient, authorization)
+		if err != nil {
+			_, outbound := runtime.MarshalerForRequest(mux, r)
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithUserClaims(ctx, user)))
+	})
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/situmorangbastian/skyros/gatewayservice/internal/ratelimit"
+)
+
+// RateLimit rejects requests over their quota with 429 Too Many Requests. Requests are
+// let through when the store fails, so an outage of the store does not take the API down.
+func RateLimit(mux *runtime.ServeMux, limiter *ratelimit.Limiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, ok, err := limiter.Take(r)
+		if err != nil {
+			log.Error().Err(err).Msg("failed rate limit Take")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.ResetAfter.Seconds()))))
+
+		if !result.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+			_, outbound := runtime.MarshalerForRequest(mux, r)
+			runtime.HTTPError(r.Context(), mux, outbound, w, r, status.Error(codes.ResourceExhausted, "Too Many Requests"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+)
+
+// Limit allows Requests per Period, refilled continuously. Up to Requests calls may be
+// made in a burst.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Result describes the state of a bucket after a request was counted against it.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next request is allowed. It is zero when Allowed.
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again.
+	ResetAfter time.Duration
+}
+
+// Store keeps token buckets. Implementations backed by a shared cache let several
+// gateway replicas enforce the same quota.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// KeyFunc identifies the client a request is counted against.
+type KeyFunc func(r *http.Request) string
+
+// ByIP counts requests per client IP.
+func ByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return "ip:" + r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// ByUser counts requests per authenticated user and falls back to the client IP for
+// anonymous requests. It relies on the claims set by the authentication middleware.
+func ByUser(r *http.Request) string {
+	user, err := auth.GetUserClaims(r.Context())
+	if err != nil {
+		return ByIP(r)
+	}
+	return "user:" + user.ID
+}
+
+// Rule applies Limit to requests matching Pattern, which uses the net/http ServeMux
+// pattern syntax, e.g. "POST /v1/users/login" or "/v1/orders/".
+type Rule struct {
+	Pattern string
+	Limit   Limit
+	Key     KeyFunc
+}
+
+// Limiter picks the most specific rule for a request and counts it in the store.
+type Limiter struct {
+	store   Store
+	matcher *http.ServeMux
+	rules   map[string]Rule
+}
+
+// NewLimiter panics on invalid or conflicting patterns, like http.ServeMux does.
+func NewLimiter(store Store, rules []Rule) *Limiter {
+	l := &Limiter{
+		store:   store,
+		matcher: http.NewServeMux(),
+		rules:   make(map[string]Rule, len(rules)),
+	}
+	for _, rule := range rules {
+		l.matcher.Handle(rule.Pattern, http.NotFoundHandler())
+		l.rules[rule.Pattern] = rule
+	}
+	return l
+}
+
+// Take counts r against its rule. ok is false when no rule matches r.
+func (l *Limiter) Take(r *http.Request) (result Result, ok bool, err error) {
+	_, pattern := l.matcher.Handler(r)
+	rule, ok := l.rules[pattern]
+	if !ok {
+		// Non-canonical paths resolve to a redirect rather than a pattern; count them
+		// against the catch-all rule so they cannot be used to dodge a quota.
+		rule, ok = l.rules["/"]
+	}
+	if !ok {
+		return Result{}, false, nil
+	}
+
+	result, err = l.store.Take(r.Context(), rule.Pattern+"|"+rule.Key(r), rule.Limit)
+	return result, true, err
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRules protects the login, registration and emailed token endpoints against
+// brute forcing and mail flooding, caps every client IP before tokens are verified, and
+// puts a generous per-user quota on everything else.
+const DefaultRules = "/=300/1m@ip; POST /v1/users/login=5/1m@ip; POST /v1/users/register/{user_type}=5/1m@ip; POST /v1/users/token/refresh=10/1m@ip; " +
+	"POST /v1/users/password/forgot=3/1m@ip; POST /v1/users/password/reset=5/1m@ip; POST /v1/users/email/verify=5/1m@ip; " +
+	"POST /v1/users/me/verification-email=3/1m@user; /=120/1m@user"
+
+// ParseRules reads rules separated by ";" in the form "<pattern>=<requests>/<period>@<key>",
+// where key is "ip" or "user", e.g. "POST /v1/users/login=5/1m@ip". Rules keyed by IP are
+// returned apart from those keyed by user, since they are enforced before authentication.
+// A pattern may appear once per key.
+func ParseRules(s string) (ipRules []Rule, userRules []Rule, err error) {
+	seen := map[string]bool{}
+	for _, raw := range strings.Split(s, ";") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		pattern, spec, ok := strings.Cut(raw, "=")
+		if !ok {
+			return nil, nil, fmt.Errorf("rate limit rule %q: missing quota", raw)
+		}
+		pattern = strings.TrimSpace(pattern)
+
+		quota, keyName, ok := strings.Cut(spec, "@")
+		if !ok {
+			return nil, nil, fmt.Errorf("rate limit rule %q: missing key", raw)
+		}
+		keyName = strings.TrimSpace(keyName)
+
+		var key KeyFunc
+		switch keyName {
+		case "ip":
+			key = ByIP
+		case "user":
+			key = ByUser
+		default:
+			return nil, nil, fmt.Errorf("rate limit rule %q: unknown key %q", raw, keyName)
+		}
+
+		if seen[keyName+" "+pattern] {
+			return nil, nil, fmt.Errorf("rate limit rule %q: duplicate pattern", raw)
+		}
+		seen[keyName+" "+pattern] = true
+
+		requests, period, ok := strings.Cut(quota, "/")
+		if !ok {
+			return nil, nil, fmt.Errorf("rate limit rule %q: quota must be <requests>/<period>", raw)
+		}
+
+		limit := Limit{}
+		limit.Requests, err = strconv.Atoi(strings.TrimSpace(requests))
+		if err != nil || limit.Requests <= 0 {
+			return nil, nil, fmt.Errorf("rate limit rule %q: invalid request count", raw)
+		}
+		limit.Period, err = time.ParseDuration(strings.TrimSpace(period))
+		if err != nil || limit.Period <= 0 {
+			return nil, nil, fmt.Errorf("rate limit rule %q: invalid period", raw)
+		}
+
+		rule := Rule{
+			Pattern: pattern,
+			Limit:   limit,
+			Key:     key,
+		}
+		if keyName == "ip" {
+			ipRules = append(ipRules, rule)
+		} else {
+			userRules = append(userRules, rule)
+		}
+	}
+
+	return ipRules, userRules, nil
+}
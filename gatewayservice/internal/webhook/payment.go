@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	paymentpb "github.com/situmorangbastian/skyros/proto/payment"
+)
+
+const (
+	// SignatureHeader carries the provider's signature of the webhook body.
+	SignatureHeader = "X-Payment-Signature"
+
+	maxPayloadBytes = 1 << 20
+)
+
+// Payment forwards payment provider webhooks to orderservice. The body is passed on
+// byte for byte because the signature is computed over it, which grpc-gateway's JSON
+// decoding would not preserve.
+func Payment(mux *runtime.ServeMux, client paymentpb.PaymentServiceClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadBytes))
+		if err != nil {
+			runtime.HTTPError(r.Context(), mux, outbound, w, r, status.Error(codes.InvalidArgument, "invalid webhook payload"))
+			return
+		}
+
+		_, err = client.HandlePaymentWebhook(r.Context(), &paymentpb.HandlePaymentWebhookRequest{
+			Provider:  pathParams["provider"],
+			Payload:   payload,
+			Signature: r.Header.Get(SignatureHeader),
+		})
+		if err != nil {
+			runtime.HTTPError(r.Context(), mux, outbound, w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -16,10 +16,20 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	grpcClient "github.com/situmorangbastian/skyros/gatewayservice/internal/integration/grpc"
+	"github.com/situmorangbastian/skyros/gatewayservice/internal/middleware"
+	"github.com/situmorangbastian/skyros/gatewayservice/internal/ratelimit"
+	"github.com/situmorangbastian/skyros/gatewayservice/internal/webhook"
+	cartpb "github.com/situmorangbastian/skyros/proto/cart"
 	orderpb "github.com/situmorangbastian/skyros/proto/order"
+	paymentpb "github.com/situmorangbastian/skyros/proto/payment"
 	productpb "github.com/situmorangbastian/skyros/proto/product"
+	promotionpb "github.com/situmorangbastian/skyros/proto/promotion"
+	shipmentpb "github.com/situmorangbastian/skyros/proto/shipment"
 	userpb "github.com/situmorangbastian/skyros/proto/user"
 	"github.com/situmorangbastian/skyros/serviceutils"
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+	"github.com/situmorangbastian/skyros/serviceutils/idempotency"
 )
 
 func main() {
@@ -41,7 +51,7 @@ func main() {
 		}
 	}
 
-	required := []string{"PORT", "USER_SERVICE_GRPC", "PRODUCT_SERVICE_GRPC", "ORDER_SERVICE_GRPC"}
+	required := []string{"PORT", "SERVICE_KEY_FILE", "USER_SERVICE_GRPC", "PRODUCT_SERVICE_GRPC", "ORDER_SERVICE_GRPC"}
 	for _, key := range required {
 		if cfg.GetString(key) == "" {
 			log.Fatal().Str("key", key).Msg("missing required config")
@@ -54,9 +64,31 @@ func main() {
 
 	mux := runtime.NewServeMux(
 		runtime.WithErrorHandler(serviceutils.NewRestErrorHandler()),
+		runtime.WithIncomingHeaderMatcher(idempotency.HeaderMatcher),
 	)
 
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	serviceKey, err := auth.ReadPrivateKey(cfg.GetString("SERVICE_KEY_FILE"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load service key")
+	}
+	serviceTokens := auth.NewServiceTokenSource("gatewayservice", serviceKey)
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			auth.ServiceClientInterceptor(serviceTokens),
+			auth.IdentityClientInterceptor(serviceTokens),
+		),
+	}
+
+	userConn, err := grpc.NewClient(cfg.GetString("USER_SERVICE_GRPC"), opts...)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to user service")
+	}
+	defer userConn.Close()
+
+	userSvcClient := userpb.NewUserServiceClient(userConn)
+	userClient := grpcClient.NewUserClient(userSvcClient)
 
 	if err := userpb.RegisterUserServiceHandlerFromEndpoint(ctx, mux, cfg.GetString("USER_SERVICE_GRPC"), opts); err != nil {
 		log.Fatal().Err(err).Msg("failed to register user service")
@@ -70,9 +102,52 @@ func main() {
 		log.Fatal().Err(err).Msg("failed to register order service")
 	}
 
+	if err := promotionpb.RegisterPromotionServiceHandlerFromEndpoint(ctx, mux, cfg.GetString("ORDER_SERVICE_GRPC"), opts); err != nil {
+		log.Fatal().Err(err).Msg("failed to register promotion service")
+	}
+
+	if err := cartpb.RegisterCartServiceHandlerFromEndpoint(ctx, mux, cfg.GetString("ORDER_SERVICE_GRPC"), opts); err != nil {
+		log.Fatal().Err(err).Msg("failed to register cart service")
+	}
+
+	if err := paymentpb.RegisterPaymentServiceHandlerFromEndpoint(ctx, mux, cfg.GetString("ORDER_SERVICE_GRPC"), opts); err != nil {
+		log.Fatal().Err(err).Msg("failed to register payment service")
+	}
+
+	if err := shipmentpb.RegisterShipmentServiceHandlerFromEndpoint(ctx, mux, cfg.GetString("ORDER_SERVICE_GRPC"), opts); err != nil {
+		log.Fatal().Err(err).Msg("failed to register shipment service")
+	}
+
+	orderConn, err := grpc.NewClient(cfg.GetString("ORDER_SERVICE_GRPC"), opts...)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to order service")
+	}
+	defer orderConn.Close()
+
+	err = mux.HandlePath(http.MethodPost, "/v1/payments/webhooks/{provider}", webhook.Payment(mux, paymentpb.NewPaymentServiceClient(orderConn)))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to register payment webhook")
+	}
+
+	rateLimits := ratelimit.DefaultRules
+	if cfg.GetString("RATE_LIMITS") != "" {
+		rateLimits = cfg.GetString("RATE_LIMITS")
+	}
+
+	ipRules, userRules, err := ratelimit.ParseRules(rateLimits)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid RATE_LIMITS")
+	}
+
+	// Per-IP quotas run before authentication so floods of bad tokens never reach token
+	// verification; per-user quotas run after it to key on the resolved user.
+	handler := middleware.RateLimit(mux, ratelimit.NewLimiter(ratelimit.NewMemoryStore(), userRules), mux)
+	handler = middleware.Authenticate(mux, auth.NewKeySet(userSvcClient), userClient, handler)
+	handler = middleware.RateLimit(mux, ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ipRules), handler)
+
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.GetInt("PORT")),
-		Handler: mux,
+		Handler: handler,
 	}
 
 	go func() {
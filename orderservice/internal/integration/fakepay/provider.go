@@ -0,0 +1,166 @@
+// Package fakepay is a deterministic PaymentProvider for local development. The outcome
+// of a payment is chosen by its payment method, so every path of the payment flow can
+// be exercised without a real provider.
+package fakepay
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/integration"
+	paymentpb "github.com/situmorangbastian/skyros/proto/payment"
+	"github.com/situmorangbastian/skyros/serviceutils/money"
+)
+
+const Name = "fake"
+
+// Payment methods understood by the provider. Any other method is declined.
+const (
+	// MethodSucceeds is authorized and captured.
+	MethodSucceeds = "pm_fake_succeeds"
+	// MethodDeclined is declined on authorization.
+	MethodDeclined = "pm_fake_declined"
+	// MethodCaptureFails is authorized but declined on capture.
+	MethodCaptureFails = "pm_fake_capture_fails"
+	// MethodAsync stays processing until a webhook settles it.
+	MethodAsync = "pm_fake_async"
+	// MethodUnavailable fails every operation as if the provider were down.
+	MethodUnavailable = "pm_fake_unavailable"
+)
+
+// ErrUnavailable is returned for operations on MethodUnavailable payments.
+var ErrUnavailable = errors.New("fakepay: provider unavailable")
+
+// Webhook is the payload of a fake provider webhook.
+type Webhook struct {
+	ID            string `json:"id"`
+	Reference     string `json:"reference"`
+	Status        string `json:"status"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+var webhookStatuses = map[string]paymentpb.PaymentStatus{
+	"requires_capture": paymentpb.PaymentStatus_PAYMENT_STATUS_REQUIRES_CAPTURE,
+	"succeeded":        paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCEEDED,
+	"failed":           paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED,
+	"refunded":         paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED,
+}
+
+type provider struct {
+	webhookSecret []byte
+}
+
+// New returns the fake provider. Webhooks must be signed with webhookSecret, see Sign.
+func New(webhookSecret string) integration.PaymentProvider {
+	return &provider{
+		webhookSecret: []byte(webhookSecret),
+	}
+}
+
+func (p *provider) Name() string {
+	return Name
+}
+
+// Authorize returns a reference that carries the payment method, which decides the
+// outcome of the later operations.
+func (p *provider) Authorize(ctx context.Context, intentID string, amount money.Money, paymentMethod string) (integration.PaymentResult, error) {
+	result := integration.PaymentResult{
+		Reference: paymentMethod + ":" + intentID,
+	}
+
+	switch paymentMethod {
+	case MethodSucceeds, MethodCaptureFails:
+		result.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_REQUIRES_CAPTURE
+	case MethodAsync:
+		result.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING
+	case MethodUnavailable:
+		return integration.PaymentResult{}, ErrUnavailable
+	default:
+		result.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED
+		result.FailureReason = "card_declined"
+	}
+
+	return result, nil
+}
+
+func (p *provider) Capture(ctx context.Context, reference string, amount money.Money) (integration.PaymentResult, error) {
+	paymentMethod, err := parseReference(reference)
+	if err != nil {
+		return integration.PaymentResult{}, err
+	}
+
+	result := integration.PaymentResult{
+		Reference: reference,
+		Status:    paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCEEDED,
+	}
+	if paymentMethod == MethodCaptureFails {
+		result.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED
+		result.FailureReason = "insufficient_funds"
+	}
+
+	return result, nil
+}
+
+func (p *provider) Refund(ctx context.Context, reference string, amount money.Money) (integration.PaymentResult, error) {
+	if _, err := parseReference(reference); err != nil {
+		return integration.PaymentResult{}, err
+	}
+
+	return integration.PaymentResult{
+		Reference: reference,
+		Status:    paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED,
+	}, nil
+}
+
+func (p *provider) ParseWebhook(payload []byte, signature string) (integration.PaymentEvent, error) {
+	if !hmac.Equal([]byte(Sign(p.webhookSecret, payload)), []byte(signature)) {
+		return integration.PaymentEvent{}, integration.ErrInvalidSignature
+	}
+
+	webhook := Webhook{}
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return integration.PaymentEvent{}, fmt.Errorf("fakepay: invalid webhook: %w", err)
+	}
+
+	paymentStatus, ok := webhookStatuses[webhook.Status]
+	if !ok || webhook.ID == "" || webhook.Reference == "" {
+		return integration.PaymentEvent{}, errors.New("fakepay: invalid webhook: id, reference and a known status are required")
+	}
+
+	_, intentID, _ := strings.Cut(webhook.Reference, ":")
+
+	return integration.PaymentEvent{
+		ID:       webhook.ID,
+		IntentID: intentID,
+		PaymentResult: integration.PaymentResult{
+			Reference:     webhook.Reference,
+			Status:        paymentStatus,
+			FailureReason: webhook.FailureReason,
+		},
+	}, nil
+}
+
+// Sign returns the signature of a webhook payload: the hex encoded HMAC-SHA256 of the
+// payload keyed with the webhook secret.
+func Sign(secret []byte, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func parseReference(reference string) (string, error) {
+	paymentMethod, _, ok := strings.Cut(reference, ":")
+	if !ok {
+		return "", fmt.Errorf("fakepay: unknown reference %q", reference)
+	}
+	if paymentMethod == MethodUnavailable {
+		return "", ErrUnavailable
+	}
+	return paymentMethod, nil
+}
@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/integration"
+	userpb "github.com/situmorangbastian/skyros/proto/user"
+)
+
+type addressClient struct {
+	userSvcClient userpb.UserServiceClient
+}
+
+func NewAddressClient(userSvcClient userpb.UserServiceClient) integration.AddressClient {
+	return &addressClient{
+		userSvcClient: userSvcClient,
+	}
+}
+
+func (ac *addressClient) GetAddress(ctx context.Context, userID string, addressID string) (string, error) {
+	resp, err := ac.userSvcClient.GetUserAddress(ctx, &userpb.GetUserAddressRequest{
+		UserId:    userID,
+		AddressId: addressID,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", integration.ErrAddressNotFound
+		}
+		return "", err
+	}
+
+	return resp.GetFormatted(), nil
+}
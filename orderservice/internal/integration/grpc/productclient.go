@@ -8,6 +8,7 @@ import (
 	productpb "github.com/situmorangbastian/skyros/proto/product"
 	userpb "github.com/situmorangbastian/skyros/proto/user"
 	"github.com/situmorangbastian/skyros/serviceutils/auth"
+	"github.com/situmorangbastian/skyros/serviceutils/money"
 )
 
 type productClient struct {
@@ -31,6 +32,39 @@ func (pc *productClient) FetchByIDs(ctx context.Context, ids []string) (map[stri
 	return toProductMap(resp.GetResult()), nil
 }
 
+func (pc *productClient) ReserveStock(ctx context.Context, items []models.OrderProduct) (string, error) {
+	stockItems := make([]*productpb.StockItem, 0, len(items))
+	for _, item := range items {
+		stockItems = append(stockItems, &productpb.StockItem{
+			ProductId: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	resp, err := pc.productSvcClient.ReserveStock(ctx, &productpb.ReserveStockRequest{
+		Items: stockItems,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.GetReservationId(), nil
+}
+
+func (pc *productClient) CommitStock(ctx context.Context, reservationID string) error {
+	_, err := pc.productSvcClient.CommitStock(ctx, &productpb.StockReservation{
+		ReservationId: reservationID,
+	})
+	return err
+}
+
+func (pc *productClient) ReleaseStock(ctx context.Context, reservationID string) error {
+	_, err := pc.productSvcClient.ReleaseStock(ctx, &productpb.StockReservation{
+		ReservationId: reservationID,
+	})
+	return err
+}
+
 func toProductMap(products []*productpb.Product) map[string]models.Product {
 	result := make(map[string]models.Product, len(products))
 	for _, p := range products {
@@ -45,7 +79,7 @@ func toProductModel(p *productpb.Product) models.Product {
 		ID:          p.GetId(),
 		Name:        p.GetName(),
 		Description: p.GetDescription(),
-		Price:       p.GetPrice(),
+		Price:       money.FromProto(p.GetPrice()),
 		Seller:      toSellerClaims(p.GetSeller()),
 	}
 }
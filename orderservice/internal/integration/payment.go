@@ -0,0 +1,41 @@
+package integration
+
+import (
+	"context"
+	"errors"
+
+	paymentpb "github.com/situmorangbastian/skyros/proto/payment"
+	"github.com/situmorangbastian/skyros/serviceutils/money"
+)
+
+// ErrInvalidSignature is returned by PaymentProvider.ParseWebhook for payloads that were
+// not signed by the provider.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// PaymentResult is the state of a payment at the provider after an operation.
+type PaymentResult struct {
+	Reference     string
+	Status        paymentpb.PaymentStatus
+	FailureReason string
+}
+
+// PaymentEvent is a verified provider callback about a payment.
+type PaymentEvent struct {
+	// ID is unique per event; providers redeliver an event with the same ID.
+	ID string
+	// IntentID is the payment intent the provider was asked to authorize, if the
+	// provider echoes it.
+	IntentID string
+	PaymentResult
+}
+
+// PaymentProvider collects payments from buyers. An error means the outcome of the
+// operation is unknown; a declined payment is a result with a FAILED status.
+type PaymentProvider interface {
+	Name() string
+	// Authorize reserves amount on the buyer's payment method for the intent intentID.
+	Authorize(ctx context.Context, intentID string, amount money.Money, paymentMethod string) (PaymentResult, error)
+	Capture(ctx context.Context, reference string, amount money.Money) (PaymentResult, error)
+	Refund(ctx context.Context, reference string, amount money.Money) (PaymentResult, error)
+	ParseWebhook(payload []byte, signature string) (PaymentEvent, error)
+}
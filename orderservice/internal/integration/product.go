@@ -8,4 +8,7 @@ import (
 
 type ProductClient interface {
 	FetchByIDs(ctx context.Context, ids []string) (map[string]models.Product, error)
+	ReserveStock(ctx context.Context, items []models.OrderProduct) (string, error)
+	CommitStock(ctx context.Context, reservationID string) error
+	ReleaseStock(ctx context.Context, reservationID string) error
 }
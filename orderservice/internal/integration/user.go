@@ -0,0 +1,14 @@
+package integration
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrAddressNotFound is returned by AddressClient.GetAddress when the user has no such address.
+var ErrAddressNotFound = errors.New("address not found")
+
+type AddressClient interface {
+	// GetAddress returns the single line rendering of an address book entry of userID.
+	GetAddress(ctx context.Context, userID string, addressID string) (string, error)
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	cartpb "github.com/situmorangbastian/skyros/proto/cart"
+	"github.com/situmorangbastian/skyros/serviceutils/money"
+)
+
+// Cart is the set of items a buyer intends to order.
+type Cart struct {
+	BuyerID string     `json:"buyer_id"`
+	Items   []CartItem `json:"items"`
+	// Total is the sum of the available items at their current prices.
+	Total     money.Money `json:"total"`
+	ExpiresAt time.Time   `json:"expires_at"`
+}
+
+type CartItem struct {
+	ProductID string `json:"product_id" validate:"required"`
+	Quantity  int64  `json:"quantity" validate:"min=1"`
+	// AddedPrice is the product's price when the item was last added or updated.
+	AddedPrice money.Money `json:"added_price"`
+	// Product is the product as it is now, zero when it is unavailable.
+	Product  Product                  `json:"product" validate:"-"`
+	Subtotal money.Money              `json:"subtotal"`
+	Warnings []cartpb.CartItemWarning `json:"warnings"`
+}
@@ -4,63 +4,154 @@ import (
 	"encoding/json"
 	"time"
 
+	orderpb "github.com/situmorangbastian/skyros/proto/order"
+	paymentpb "github.com/situmorangbastian/skyros/proto/payment"
 	"github.com/situmorangbastian/skyros/serviceutils/auth"
+	"github.com/situmorangbastian/skyros/serviceutils/money"
+	"github.com/situmorangbastian/skyros/serviceutils/pagination"
 )
 
 type Product struct {
 	ID          string      `json:"id"`
 	Name        string      `json:"name" validate:"required"`
 	Description string      `json:"description" validate:"required"`
-	Price       int32       `json:"price" validate:"required"`
+	Price       money.Money `json:"price" validate:"-"`
 	Seller      auth.Claims `json:"seller" validate:"-"`
 }
 type Order struct {
-	ID                 string         `json:"id"`
-	Buyer              auth.Claims    `json:"buyer" validate:"-"`
-	Seller             auth.Claims    `json:"seller" validate:"-"`
-	Description        string         `json:"description"`
-	SourceAddress      string         `json:"source_address"`
-	DestinationAddress string         `json:"destination_address" validate:"required"`
-	Items              []OrderProduct `json:"items" validate:"required,min=1"`
-	TotalPrice         int64          `json:"total_price"`
-	Status             int            `json:"status"`
-	CreatedAt          time.Time      `json:"created_at"`
-	UpdatedAt          time.Time      `json:"updated_at"`
+	ID                 string                  `json:"id"`
+	CheckoutID         string                  `json:"checkout_id"`
+	Buyer              auth.Claims             `json:"buyer" validate:"-"`
+	Seller             auth.Claims             `json:"seller" validate:"-"`
+	Description        string                  `json:"description"`
+	SourceAddress      string                  `json:"source_address"`
+	DestinationAddress string                  `json:"destination_address"`
+	Items              []OrderProduct          `json:"items" validate:"required,min=1"`
+	Subtotal           money.Money             `json:"subtotal"`
+	Discounts          []OrderDiscount         `json:"discounts"`
+	TotalPrice         money.Money             `json:"total_price"`
+	Status             orderpb.OrderStatus     `json:"status"`
+	PaymentStatus      paymentpb.PaymentStatus `json:"payment_status"`
+	// AddressID is the buyer's address book entry requested when placing the order; it
+	// is resolved into DestinationAddress.
+	AddressID string `json:"-" validate:"required"`
+	// CouponCode is the coupon requested when placing the order.
+	CouponCode         string    `json:"-"`
+	StockReservationID string    `json:"-"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
 }
 
 func (o Order) MarshalJSON() ([]byte, error) {
-	status := "pending"
-	if o.Status == 1 {
-		status = "accepted"
-	}
-
 	return json.Marshal(&struct {
-		ID                 string         `json:"id"`
-		Buyer              auth.Claims    `json:"buyer" validate:"-"`
-		Seller             auth.Claims    `json:"seller" validate:"-"`
-		Description        string         `json:"description"`
-		SourceAddress      string         `json:"source_address"`
-		DestinationAddress string         `json:"destination_address" validate:"required"`
-		Items              []OrderProduct `json:"items" validate:"required,min=1"`
-		TotalPrice         int64          `json:"total_price"`
-		Status             string         `json:"status"`
-		CreatedAt          time.Time      `json:"created_at"`
-		UpdatedAt          time.Time      `json:"updated_at"`
+		ID                 string          `json:"id"`
+		CheckoutID         string          `json:"checkout_id"`
+		Buyer              auth.Claims     `json:"buyer" validate:"-"`
+		Seller             auth.Claims     `json:"seller" validate:"-"`
+		Description        string          `json:"description"`
+		SourceAddress      string          `json:"source_address"`
+		DestinationAddress string          `json:"destination_address"`
+		Items              []OrderProduct  `json:"items" validate:"required,min=1"`
+		Subtotal           money.Money     `json:"subtotal"`
+		Discounts          []OrderDiscount `json:"discounts"`
+		TotalPrice         money.Money     `json:"total_price"`
+		Status             string          `json:"status"`
+		PaymentStatus      string          `json:"payment_status"`
+		CreatedAt          time.Time       `json:"created_at"`
+		UpdatedAt          time.Time       `json:"updated_at"`
 	}{
 		ID:                 o.ID,
+		CheckoutID:         o.CheckoutID,
 		Buyer:              o.Buyer,
 		Seller:             o.Seller,
 		Description:        o.Description,
 		SourceAddress:      o.SourceAddress,
 		DestinationAddress: o.DestinationAddress,
 		Items:              o.Items,
+		Subtotal:           o.Subtotal,
+		Discounts:          o.Discounts,
 		TotalPrice:         o.TotalPrice,
-		Status:             status,
+		Status:             o.Status.String(),
+		PaymentStatus:      o.PaymentStatus.String(),
 		CreatedAt:          o.CreatedAt,
 		UpdatedAt:          o.UpdatedAt,
 	})
 }
 
+// orderStatusTransitions lists, for every status, the statuses an order may move to next.
+// Delivered, cancelled and rejected orders are final. Sellers may still accept pending
+// orders, which are then paid outside of payment intents.
+var orderStatusTransitions = map[orderpb.OrderStatus][]orderpb.OrderStatus{
+	orderpb.OrderStatus_ORDER_STATUS_PENDING: {
+		orderpb.OrderStatus_ORDER_STATUS_PAID,
+		orderpb.OrderStatus_ORDER_STATUS_ACCEPTED,
+		orderpb.OrderStatus_ORDER_STATUS_REJECTED,
+		orderpb.OrderStatus_ORDER_STATUS_CANCELLED,
+	},
+	orderpb.OrderStatus_ORDER_STATUS_PAID: {
+		orderpb.OrderStatus_ORDER_STATUS_ACCEPTED,
+		orderpb.OrderStatus_ORDER_STATUS_REJECTED,
+		orderpb.OrderStatus_ORDER_STATUS_CANCELLED,
+	},
+	orderpb.OrderStatus_ORDER_STATUS_ACCEPTED: {
+		orderpb.OrderStatus_ORDER_STATUS_SHIPPED,
+		orderpb.OrderStatus_ORDER_STATUS_CANCELLED,
+	},
+	orderpb.OrderStatus_ORDER_STATUS_SHIPPED: {
+		orderpb.OrderStatus_ORDER_STATUS_DELIVERED,
+	},
+}
+
+// CanTransitionStatus reports whether an order in status from may move to status to.
+func CanTransitionStatus(from, to orderpb.OrderStatus) bool {
+	for _, next := range orderStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Checkout groups the orders placed together from a single cart, one order per seller.
+type Checkout struct {
+	ID         string      `json:"id"`
+	Buyer      auth.Claims `json:"buyer"`
+	Orders     []Order     `json:"orders"`
+	TotalPrice money.Money `json:"total_price"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// StatusChange moves an order from one status to another on behalf of an actor. The
+// order status history is made of these entries, starting with the order's creation.
+type StatusChange struct {
+	OrderID   string
+	From      orderpb.OrderStatus
+	To        orderpb.OrderStatus
+	ActorID   string
+	ActorType auth.UserType
+	Reason    string
+	CreatedAt time.Time
+	// Refund, when set, is recorded against the order in the same transaction.
+	Refund *Refund
+}
+
+type RefundStatus string
+
+const (
+	RefundPending   RefundStatus = "pending"
+	RefundCompleted RefundStatus = "completed"
+)
+
+// Refund is money owed back to the buyer of a paid order that will not be fulfilled.
+type Refund struct {
+	ID        string       `json:"id"`
+	OrderID   string       `json:"order_id"`
+	Amount    money.Money  `json:"amount"`
+	Reason    string       `json:"reason"`
+	Status    RefundStatus `json:"status"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
 type OrderProduct struct {
 	Product   Product `json:"-" validate:"-"`
 	ProductID string  `json:"product_id" validate:"required"`
@@ -77,11 +168,29 @@ func (op OrderProduct) MarshalJSON() ([]byte, error) {
 	})
 }
 
+type OrderSort int
+
+const (
+	OrderSortNewest OrderSort = iota
+	OrderSortOldest
+	OrderSortTotalPriceAsc
+	OrderSortTotalPriceDesc
+)
+
 type Filter struct {
-	Page     int
+	// After is the last order of the previous page, nil for the first page.
+	After    *pagination.Cursor
 	PageSize int
 	Search   string
 	SellerID string
 	BuyerID  string
 	OrderID  string
+	// Statuses matches orders in any of the listed statuses.
+	Statuses []orderpb.OrderStatus
+	// CreatedAfter (inclusive) and CreatedBefore (exclusive) bound created_at when set.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	ProductID     string
+	CurrencyCode  string
+	Sort          OrderSort
 }
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	paymentpb "github.com/situmorangbastian/skyros/proto/payment"
+	"github.com/situmorangbastian/skyros/serviceutils/money"
+)
+
+// PaymentIntent is one attempt to pay for an order through a payment provider.
+type PaymentIntent struct {
+	ID                string                  `json:"id"`
+	OrderID           string                  `json:"order_id"`
+	BuyerID           string                  `json:"buyer_id"`
+	Provider          string                  `json:"provider"`
+	ProviderReference string                  `json:"-"`
+	Amount            money.Money             `json:"amount"`
+	Status            paymentpb.PaymentStatus `json:"status"`
+	FailureReason     string                  `json:"failure_reason"`
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+}
+
+// PaymentUpdate moves a payment intent from one status to another after a provider
+// operation or webhook.
+type PaymentUpdate struct {
+	IntentID string
+	From     paymentpb.PaymentStatus
+	To       paymentpb.PaymentStatus
+	// ProviderReference, when set, replaces the intent's reference.
+	ProviderReference string
+	FailureReason     string
+	// EventID identifies the provider webhook that reported the update. Updates
+	// carrying an event that was already applied are ignored.
+	EventID string
+}
+
+// paymentStatusTransitions lists, for every payment status, the statuses a payment may
+// move to next. Failed and refunded payments are final.
+var paymentStatusTransitions = map[paymentpb.PaymentStatus][]paymentpb.PaymentStatus{
+	paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING: {
+		paymentpb.PaymentStatus_PAYMENT_STATUS_REQUIRES_CAPTURE,
+		paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCEEDED,
+		paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED,
+	},
+	paymentpb.PaymentStatus_PAYMENT_STATUS_REQUIRES_CAPTURE: {
+		paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCEEDED,
+		paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED,
+	},
+	paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCEEDED: {
+		paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED,
+	},
+}
+
+// CanTransitionPayment reports whether a payment in status from may move to status to.
+func CanTransitionPayment(from, to paymentpb.PaymentStatus) bool {
+	for _, next := range paymentStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
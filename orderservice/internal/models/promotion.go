@@ -0,0 +1,74 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	promotionpb "github.com/situmorangbastian/skyros/proto/promotion"
+	"github.com/situmorangbastian/skyros/serviceutils/money"
+)
+
+// Coupon is a discount code a seller offers on their own orders.
+type Coupon struct {
+	ID           string                   `json:"id"`
+	Code         string                   `json:"code"`
+	SellerID     string                   `json:"seller_id"`
+	DiscountType promotionpb.DiscountType `json:"discount_type"`
+	// PercentOff is the discount in percent, from 1 to 100, for percentage coupons.
+	PercentOff int32 `json:"percent_off"`
+	// AmountOff is the discount of fixed coupons.
+	AmountOff money.Money `json:"amount_off"`
+	// MinOrderValue is the smallest order subtotal the coupon applies to, zero for none.
+	MinOrderValue money.Money `json:"min_order_value"`
+	StartsAt      time.Time   `json:"starts_at"`
+	// EndsAt is exclusive, zero when the coupon never expires.
+	EndsAt time.Time `json:"ends_at"`
+	// MaxRedemptions and MaxRedemptionsPerBuyer are 0 when unlimited.
+	MaxRedemptions         int64     `json:"max_redemptions"`
+	MaxRedemptionsPerBuyer int64     `json:"max_redemptions_per_buyer"`
+	Redemptions            int64     `json:"redemptions"`
+	CreatedAt              time.Time `json:"created_at"`
+}
+
+// NormalizeCouponCode returns the form coupon codes are stored and looked up in, so
+// codes are matched case-insensitively.
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ActiveAt reports whether the coupon can be redeemed at t.
+func (c Coupon) ActiveAt(t time.Time) bool {
+	return !t.Before(c.StartsAt) && (c.EndsAt.IsZero() || t.Before(c.EndsAt))
+}
+
+// Discount returns how much the coupon takes off an order of subtotal. The discount
+// never exceeds the subtotal.
+func (c Coupon) Discount(subtotal money.Money) (money.Money, error) {
+	var discount money.Money
+	switch c.DiscountType {
+	case promotionpb.DiscountType_DISCOUNT_TYPE_PERCENTAGE:
+		scaled, err := subtotal.Multiply(int64(c.PercentOff))
+		if err != nil {
+			return money.Money{}, err
+		}
+		discount = money.New(subtotal.CurrencyCode, scaled.MinorUnits/100)
+	case promotionpb.DiscountType_DISCOUNT_TYPE_FIXED:
+		if c.AmountOff.CurrencyCode != subtotal.CurrencyCode {
+			return money.Money{}, money.ErrCurrencyMismatch
+		}
+		discount = c.AmountOff
+	}
+
+	if discount.MinorUnits > subtotal.MinorUnits {
+		discount = subtotal
+	}
+
+	return discount, nil
+}
+
+// OrderDiscount is a discount line of an order.
+type OrderDiscount struct {
+	CouponID string      `json:"coupon_id"`
+	Code     string      `json:"code"`
+	Amount   money.Money `json:"amount"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	shipmentpb "github.com/situmorangbastian/skyros/proto/shipment"
+)
+
+// Shipment is one package of an order on its way to the buyer.
+type Shipment struct {
+	ID                 string                    `json:"id"`
+	OrderID            string                    `json:"order_id"`
+	Carrier            string                    `json:"carrier" validate:"required,max=64"`
+	TrackingNumber     string                    `json:"tracking_number" validate:"required,max=128"`
+	SourceAddress      string                    `json:"source_address"`
+	DestinationAddress string                    `json:"destination_address"`
+	Status             shipmentpb.ShipmentStatus `json:"status"`
+	Items              []ShipmentItem            `json:"items" validate:"dive"`
+	Events             []TrackingEvent           `json:"events"`
+	CreatedAt          time.Time                 `json:"created_at"`
+	UpdatedAt          time.Time                 `json:"updated_at"`
+}
+
+type ShipmentItem struct {
+	ProductID string `json:"product_id" validate:"required"`
+	Quantity  int64  `json:"quantity" validate:"min=1"`
+}
+
+// TrackingEvent is a carrier update about a shipment.
+type TrackingEvent struct {
+	Status      shipmentpb.ShipmentStatus `json:"status"`
+	Location    string                    `json:"location" validate:"max=255"`
+	Description string                    `json:"description" validate:"max=1000"`
+	OccurredAt  time.Time                 `json:"occurred_at"`
+	CreatedAt   time.Time                 `json:"created_at"`
+}
+
+// CanTrackShipment reports whether a tracking event in status to may be recorded on a
+// shipment in status from. Events may repeat a status, e.g. successive in transit scans.
+func CanTrackShipment(from, to shipmentpb.ShipmentStatus) bool {
+	switch from {
+	case shipmentpb.ShipmentStatus_SHIPMENT_STATUS_DELIVERED, shipmentpb.ShipmentStatus_SHIPMENT_STATUS_RETURNED:
+		return false
+	}
+
+	switch to {
+	case shipmentpb.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT,
+		shipmentpb.ShipmentStatus_SHIPMENT_STATUS_OUT_FOR_DELIVERY,
+		shipmentpb.ShipmentStatus_SHIPMENT_STATUS_DELIVERY_FAILED,
+		shipmentpb.ShipmentStatus_SHIPMENT_STATUS_DELIVERED,
+		shipmentpb.ShipmentStatus_SHIPMENT_STATUS_RETURNED:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	"github.com/situmorangbastian/skyros/orderservice/internal/repository"
+)
+
+type cartRepository struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewCartRepository(dbpool *pgxpool.Pool) repository.CartRepository {
+	return &cartRepository{
+		dbpool: dbpool,
+	}
+}
+
+func (r *cartRepository) Get(ctx context.Context, buyerID string) (models.Cart, error) {
+	cart := models.Cart{
+		BuyerID: buyerID,
+		Items:   []models.CartItem{},
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select("expires_at").
+		From("carts").
+		Where(sq.Eq{"buyer_id": buyerID}).
+		Where(sq.Gt{"expires_at": time.Now().UTC()}).
+		ToSql()
+	if err != nil {
+		return models.Cart{}, err
+	}
+
+	err = r.dbpool.QueryRow(ctx, query, args...).Scan(&cart.ExpiresAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return cart, nil
+		}
+		return models.Cart{}, err
+	}
+
+	query, args, err = psql.Select("product_id", "quantity", "unit_price", "currency").
+		From("cart_items").
+		Where(sq.Eq{"buyer_id": buyerID}).
+		OrderBy("created_at ASC", "product_id ASC").
+		ToSql()
+	if err != nil {
+		return models.Cart{}, err
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return models.Cart{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.CartItem{}
+		err = rows.Scan(
+			&item.ProductID,
+			&item.Quantity,
+			&item.AddedPrice.MinorUnits,
+			&item.AddedPrice.CurrencyCode,
+		)
+		if err != nil {
+			return models.Cart{}, err
+		}
+		cart.Items = append(cart.Items, item)
+	}
+
+	return cart, rows.Err()
+}
+
+func (r *cartRepository) AddItem(ctx context.Context, buyerID string, item models.CartItem, expiresAt time.Time) error {
+	return r.change(ctx, buyerID, expiresAt, func(tx pgx.Tx, timeNow time.Time) error {
+		psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+		query, args, err := psql.Insert("cart_items").
+			Columns("buyer_id", "product_id", "quantity", "unit_price", "currency", "created_at", "updated_at").
+			Values(buyerID, item.ProductID, item.Quantity, item.AddedPrice.MinorUnits, item.AddedPrice.CurrencyCode, timeNow, timeNow).
+			Suffix(`ON CONFLICT (buyer_id, product_id) DO UPDATE SET
+				quantity = cart_items.quantity + EXCLUDED.quantity,
+				unit_price = EXCLUDED.unit_price,
+				currency = EXCLUDED.currency,
+				updated_at = EXCLUDED.updated_at`).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, query, args...)
+		return err
+	})
+}
+
+func (r *cartRepository) UpdateItem(ctx context.Context, buyerID string, item models.CartItem, expiresAt time.Time) error {
+	return r.change(ctx, buyerID, expiresAt, func(tx pgx.Tx, timeNow time.Time) error {
+		psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+		query, args, err := psql.Update("cart_items").
+			Set("quantity", item.Quantity).
+			Set("unit_price", item.AddedPrice.MinorUnits).
+			Set("currency", item.AddedPrice.CurrencyCode).
+			Set("updated_at", timeNow).
+			Where(sq.Eq{
+				"buyer_id":   buyerID,
+				"product_id": item.ProductID,
+			}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if result.RowsAffected() == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *cartRepository) RemoveItem(ctx context.Context, buyerID string, productID string, expiresAt time.Time) error {
+	return r.change(ctx, buyerID, expiresAt, func(tx pgx.Tx, timeNow time.Time) error {
+		psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+		query, args, err := psql.Delete("cart_items").
+			Where(sq.Eq{
+				"buyer_id":   buyerID,
+				"product_id": productID,
+			}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if result.RowsAffected() == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *cartRepository) Delete(ctx context.Context, buyerID string) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Delete("carts").
+		Where(sq.Eq{"buyer_id": buyerID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.dbpool.Exec(ctx, query, args...)
+	return err
+}
+
+// change runs apply in a transaction after discarding the buyer's cart if it has
+// expired and extending it, or creating it, until expiresAt.
+func (r *cartRepository) change(ctx context.Context, buyerID string, expiresAt time.Time, apply func(tx pgx.Tx, timeNow time.Time) error) error {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	timeNow := time.Now().UTC()
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Delete("carts").
+		Where(sq.Eq{"buyer_id": buyerID}).
+		Where(sq.LtOrEq{"expires_at": timeNow}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+
+	query, args, err = psql.Insert("carts").
+		Columns("buyer_id", "expires_at", "created_at", "updated_at").
+		Values(buyerID, expiresAt, timeNow, timeNow).
+		Suffix("ON CONFLICT (buyer_id) DO UPDATE SET expires_at = EXCLUDED.expires_at, updated_at = EXCLUDED.updated_at").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+
+	if err = apply(tx, timeNow); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -2,6 +2,8 @@ package postgresql
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -13,6 +15,9 @@ import (
 
 	"github.com/situmorangbastian/skyros/orderservice/internal/models"
 	"github.com/situmorangbastian/skyros/orderservice/internal/repository"
+	eventspb "github.com/situmorangbastian/skyros/proto/events"
+	orderpb "github.com/situmorangbastian/skyros/proto/order"
+	"github.com/situmorangbastian/skyros/serviceutils/outbox"
 )
 
 type orderRepository struct {
@@ -25,13 +30,80 @@ func NewOrderRepository(dbpool *pgxpool.Pool) repository.OrderRepository {
 	}
 }
 
-func (r *orderRepository) Store(ctx context.Context, order models.Order) (models.Order, error) {
+// StoreCheckout persists the checkout together with all of its orders in a single
+// transaction, so a cart is either fully placed or not placed at all.
+func (r *orderRepository) StoreCheckout(ctx context.Context, checkout models.Checkout) (models.Checkout, error) {
 	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return models.Order{}, err
+		return models.Checkout{}, err
 	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
 
 	timeNow := time.Now().UTC()
+	checkout.ID = uuid.New().String()
+	checkout.CreatedAt = timeNow
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Insert("checkouts").
+		Columns(
+			"id",
+			"buyer_id",
+			"total_price",
+			"currency",
+			"created_at",
+			"updated_at",
+		).
+		Values(
+			checkout.ID,
+			checkout.Buyer.ID,
+			checkout.TotalPrice.MinorUnits,
+			checkout.TotalPrice.CurrencyCode,
+			timeNow,
+			timeNow,
+		).ToSql()
+	if err != nil {
+		return models.Checkout{}, err
+	}
+
+	_, err = tx.Exec(ctx, query, args...)
+	if err != nil {
+		return models.Checkout{}, err
+	}
+
+	for index := range checkout.Orders {
+		checkout.Orders[index].CheckoutID = checkout.ID
+		checkout.Orders[index], err = r.storeOrder(ctx, tx, checkout.Orders[index], timeNow)
+		if err != nil {
+			return models.Checkout{}, err
+		}
+
+		err = insertStatusHistory(ctx, tx, models.StatusChange{
+			OrderID:   checkout.Orders[index].ID,
+			From:      orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED,
+			To:        checkout.Orders[index].Status,
+			ActorID:   checkout.Buyer.ID,
+			ActorType: checkout.Buyer.Type,
+			CreatedAt: timeNow,
+		})
+		if err != nil {
+			return models.Checkout{}, err
+		}
+
+		if err = outbox.Write(ctx, tx, checkout.Orders[index].ID, orderCreatedEvent(checkout.Orders[index])); err != nil {
+			return models.Checkout{}, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return models.Checkout{}, err
+	}
+
+	return checkout, nil
+}
+
+func (r *orderRepository) storeOrder(ctx context.Context, tx pgx.Tx, order models.Order, timeNow time.Time) (models.Order, error) {
 	order.ID = uuid.New().String()
 	order.CreatedAt = timeNow
 	order.UpdatedAt = timeNow
@@ -40,25 +112,33 @@ func (r *orderRepository) Store(ctx context.Context, order models.Order) (models
 	query, args, err := psql.Insert("orders").
 		Columns(
 			"id",
+			"checkout_id",
 			"buyer_id",
 			"seller_id",
 			"description",
 			"source_address",
 			"destination_address",
+			"subtotal",
 			"total_price",
+			"currency",
 			"status",
+			"stock_reservation_id",
 			"created_at",
 			"updated_at",
 		).
 		Values(
 			order.ID,
+			order.CheckoutID,
 			order.Buyer.ID,
 			order.Seller.ID,
 			order.Description,
 			order.SourceAddress,
 			order.DestinationAddress,
-			order.TotalPrice,
+			order.Subtotal.MinorUnits,
+			order.TotalPrice.MinorUnits,
+			order.TotalPrice.CurrencyCode,
 			order.Status,
+			order.StockReservationID,
 			order.CreatedAt,
 			order.UpdatedAt,
 		).ToSql()
@@ -72,13 +152,16 @@ func (r *orderRepository) Store(ctx context.Context, order models.Order) (models
 	}
 
 	for _, orderItem := range order.Items {
-		psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 		query, args, err := psql.Insert("orders_products").
 			Columns(
 				"id",
 				"order_id",
 				"product_id",
 				"quantity",
+				"product_name",
+				"unit_price",
+				"seller_id",
+				"seller_name",
 				"created_at",
 				"updated_at",
 			).
@@ -87,6 +170,10 @@ func (r *orderRepository) Store(ctx context.Context, order models.Order) (models
 				order.ID,
 				orderItem.ProductID,
 				orderItem.Quantity,
+				orderItem.Product.Name,
+				orderItem.Product.Price.MinorUnits,
+				order.Seller.ID,
+				orderItem.Product.Seller.Name,
 				timeNow,
 				timeNow,
 			).ToSql()
@@ -100,32 +187,185 @@ func (r *orderRepository) Store(ctx context.Context, order models.Order) (models
 		}
 	}
 
-	if err = tx.Commit(ctx); err != nil {
-		return models.Order{}, err
+	for _, discount := range order.Discounts {
+		if err := redeemCoupon(ctx, tx, order, discount, timeNow); err != nil {
+			return models.Order{}, err
+		}
 	}
 
 	return order, nil
 }
 
+func (r *orderRepository) fetchDiscounts(ctx context.Context, orderID string) ([]models.OrderDiscount, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select("coupon_id", "code", "amount", "currency").
+		From("order_discounts").
+		Where(sq.Eq{"order_id": orderID}).
+		OrderBy("created_at ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	discounts := []models.OrderDiscount{}
+	for rows.Next() {
+		discount := models.OrderDiscount{}
+		err = rows.Scan(
+			&discount.CouponID,
+			&discount.Code,
+			&discount.Amount.MinorUnits,
+			&discount.Amount.CurrencyCode,
+		)
+		if err != nil {
+			return nil, err
+		}
+		discounts = append(discounts, discount)
+	}
+
+	return discounts, rows.Err()
+}
+
+// redeemCoupon counts one use of the discount's coupon and records the discount line.
+// Incrementing the coupon row enforces the global limit and locks the row until the
+// transaction ends, so concurrent checkouts redeeming the same coupon are serialized
+// and the per buyer count that follows cannot be raced past.
+func redeemCoupon(ctx context.Context, tx pgx.Tx, order models.Order, discount models.OrderDiscount, timeNow time.Time) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Update("coupons").
+		Set("redemptions", sq.Expr("redemptions + 1")).
+		Set("updated_at", timeNow).
+		Where(sq.Eq{"id": discount.CouponID}).
+		Where("(max_redemptions = 0 OR redemptions < max_redemptions)").
+		Suffix("RETURNING max_redemptions_per_buyer").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var maxPerBuyer int64
+	err = tx.QueryRow(ctx, query, args...).Scan(&maxPerBuyer)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repository.ErrCouponLimitReached
+		}
+		return err
+	}
+
+	if maxPerBuyer > 0 {
+		query, args, err = psql.Select("COUNT(*)").
+			From("order_discounts").
+			Where(sq.Eq{
+				"coupon_id": discount.CouponID,
+				"buyer_id":  order.Buyer.ID,
+			}).
+			Where("released_at IS NULL").
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		var redeemed int64
+		if err = tx.QueryRow(ctx, query, args...).Scan(&redeemed); err != nil {
+			return err
+		}
+
+		if redeemed >= maxPerBuyer {
+			return repository.ErrCouponLimitReached
+		}
+	}
+
+	query, args, err = psql.Insert("order_discounts").
+		Columns("id", "order_id", "coupon_id", "buyer_id", "code", "amount", "currency", "created_at").
+		Values(uuid.New().String(), order.ID, discount.CouponID, order.Buyer.ID, discount.Code, discount.Amount.MinorUnits, discount.Amount.CurrencyCode, timeNow).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, query, args...)
+	return err
+}
+
+// releaseCoupons gives back the coupon uses of an order that was cancelled or rejected,
+// so they count neither against the coupon's limit nor against the buyer's.
+func releaseCoupons(ctx context.Context, tx pgx.Tx, orderID string, timeNow time.Time) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Update("order_discounts").
+		Set("released_at", timeNow).
+		Where(sq.Eq{"order_id": orderID}).
+		Where("released_at IS NULL").
+		Suffix("RETURNING coupon_id").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	couponIDs := []string{}
+	for rows.Next() {
+		var couponID string
+		if err = rows.Scan(&couponID); err != nil {
+			rows.Close()
+			return err
+		}
+		couponIDs = append(couponIDs, couponID)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, couponID := range couponIDs {
+		query, args, err = psql.Update("coupons").
+			Set("redemptions", sq.Expr("GREATEST(redemptions - 1, 0)")).
+			Set("updated_at", timeNow).
+			Where(sq.Eq{"id": couponID}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.Exec(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *orderRepository) Fetch(ctx context.Context, filter models.Filter) ([]models.Order, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	qBuilder := psql.Select(
 		"id",
+		"COALESCE(checkout_id::text, '')",
 		"buyer_id",
 		"seller_id",
 		"description",
 		"source_address",
 		"destination_address",
+		"subtotal",
 		"total_price",
+		"currency",
 		"status",
+		"payment_status",
+		"stock_reservation_id",
 		"created_at",
 		"updated_at",
-	).From("orders").OrderBy("created_at DESC")
+	).From("orders").
+		Limit(uint64(filter.PageSize))
 
-	offset := (filter.Page - 1) * filter.PageSize
-	qBuilder = qBuilder.Limit(uint64(filter.PageSize))
-	if offset > 0 {
-		qBuilder.Offset(uint64(offset))
+	if filter.BuyerID != "" {
+		qBuilder = qBuilder.Where(sq.Eq{"buyer_id": filter.BuyerID})
 	}
 
 	if filter.SellerID != "" {
@@ -136,6 +376,56 @@ func (r *orderRepository) Fetch(ctx context.Context, filter models.Filter) ([]mo
 		qBuilder = qBuilder.Where(sq.Eq{"id": filter.OrderID})
 	}
 
+	if len(filter.Statuses) > 0 {
+		qBuilder = qBuilder.Where(sq.Eq{"status": filter.Statuses})
+	}
+
+	if !filter.CreatedAfter.IsZero() {
+		qBuilder = qBuilder.Where(sq.GtOrEq{"created_at": filter.CreatedAfter})
+	}
+
+	if !filter.CreatedBefore.IsZero() {
+		qBuilder = qBuilder.Where(sq.Lt{"created_at": filter.CreatedBefore})
+	}
+
+	if filter.ProductID != "" {
+		qBuilder = qBuilder.Where("EXISTS (SELECT 1 FROM orders_products WHERE orders_products.order_id = orders.id AND orders_products.product_id = ?)", filter.ProductID)
+	}
+
+	if filter.Search != "" {
+		qBuilder = qBuilder.Where(sq.ILike{"description": "%" + escapeLike(filter.Search) + "%"})
+	}
+
+	if filter.CurrencyCode != "" {
+		qBuilder = qBuilder.Where(sq.Eq{"currency": filter.CurrencyCode})
+	}
+
+	switch filter.Sort {
+	case models.OrderSortOldest:
+		qBuilder = qBuilder.OrderBy("created_at ASC", "id ASC")
+		if filter.After != nil {
+			qBuilder = qBuilder.Where("(created_at, id) > (?, ?)", filter.After.CreatedAt, filter.After.ID)
+		}
+	case models.OrderSortTotalPriceAsc, models.OrderSortTotalPriceDesc:
+		direction, comparison := "ASC", ">"
+		if filter.Sort == models.OrderSortTotalPriceDesc {
+			direction, comparison = "DESC", "<"
+		}
+		qBuilder = qBuilder.OrderBy("total_price "+direction, "id "+direction)
+		if filter.After != nil {
+			afterTotalPrice, err := strconv.ParseInt(filter.After.Key, 10, 64)
+			if err != nil {
+				return []models.Order{}, err
+			}
+			qBuilder = qBuilder.Where("(total_price, id) "+comparison+" (?, ?)", afterTotalPrice, filter.After.ID)
+		}
+	default:
+		qBuilder = qBuilder.OrderBy("created_at DESC", "id DESC")
+		if filter.After != nil {
+			qBuilder = qBuilder.Where("(created_at, id) < (?, ?)", filter.After.CreatedAt, filter.After.ID)
+		}
+	}
+
 	query, args, err := qBuilder.ToSql()
 	if err != nil {
 		return []models.Order{}, err
@@ -151,13 +441,18 @@ func (r *orderRepository) Fetch(ctx context.Context, filter models.Filter) ([]mo
 		order := models.Order{}
 		err = rows.Scan(
 			&order.ID,
+			&order.CheckoutID,
 			&order.Buyer.ID,
 			&order.Seller.ID,
 			&order.Description,
 			&order.SourceAddress,
 			&order.DestinationAddress,
-			&order.TotalPrice,
+			&order.Subtotal.MinorUnits,
+			&order.TotalPrice.MinorUnits,
+			&order.TotalPrice.CurrencyCode,
 			&order.Status,
+			&order.PaymentStatus,
+			&order.StockReservationID,
 			&order.CreatedAt,
 			&order.UpdatedAt,
 		)
@@ -165,6 +460,7 @@ func (r *orderRepository) Fetch(ctx context.Context, filter models.Filter) ([]mo
 			return []models.Order{}, err
 		}
 
+		order.Subtotal.CurrencyCode = order.TotalPrice.CurrencyCode
 		orders = append(orders, order)
 	}
 
@@ -179,6 +475,10 @@ func (r *orderRepository) Fetch(ctx context.Context, filter models.Filter) ([]mo
 			query, args, err := psql.Select(
 				"product_id",
 				"quantity",
+				"product_name",
+				"unit_price",
+				"COALESCE(seller_id::text, '')",
+				"seller_name",
 			).
 				From("orders_products").
 				Where(sq.Eq{"order_id": order.ID}).
@@ -198,18 +498,34 @@ func (r *orderRepository) Fetch(ctx context.Context, filter models.Filter) ([]mo
 				err = rows.Scan(
 					&orderProduct.ProductID,
 					&orderProduct.Quantity,
+					&orderProduct.Product.Name,
+					&orderProduct.Product.Price.MinorUnits,
+					&orderProduct.Product.Seller.ID,
+					&orderProduct.Product.Seller.Name,
 				)
 				if err != nil {
 					log.Error(err)
 					continue
 				}
 
+				orderProduct.Product.ID = orderProduct.ProductID
+				orderProduct.Product.Price.CurrencyCode = order.TotalPrice.CurrencyCode
 				orderProducts = append(orderProducts, orderProduct)
 			}
 
 			orders[index].Items = orderProducts
 			return nil
 		})
+
+		errGroup.Go(func() error {
+			discounts, err := r.fetchDiscounts(ctx, order.ID)
+			if err != nil {
+				return err
+			}
+
+			orders[index].Discounts = discounts
+			return nil
+		})
 	}
 
 	if err := errGroup.Wait(); err != nil {
@@ -223,27 +539,156 @@ func (r *orderRepository) Fetch(ctx context.Context, filter models.Filter) ([]mo
 	return orders, nil
 }
 
-func (r *orderRepository) PatchStatus(ctx context.Context, ID string, status int) error {
+func (r *orderRepository) PatchStatus(ctx context.Context, change models.StatusChange) error {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err = patchStatus(ctx, tx, change, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// patchStatus is PatchStatus within tx, for callers that change an order's status as
+// part of a larger transaction.
+func patchStatus(ctx context.Context, tx pgx.Tx, change models.StatusChange, timeNow time.Time) error {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	query, args, err := psql.Update("orders").
-		Set("status", status).
-		Set("updated_time", time.Now().UTC()).
+		Set("status", change.To).
+		Set("updated_at", timeNow).
 		Where(sq.Eq{
-			"id": ID,
+			"id":     change.OrderID,
+			"status": change.From,
 		}).
+		Suffix("RETURNING buyer_id, seller_id").
 		ToSql()
 	if err != nil {
 		return err
 	}
 
-	result, err := r.dbpool.Exec(ctx, query, args...)
+	var buyerID, sellerID string
+	err = tx.QueryRow(ctx, query, args...).Scan(&buyerID, &sellerID)
 	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repository.ErrNotFound
+		}
 		return err
 	}
 
-	if result.RowsAffected() == 0 {
-		return repository.ErrNotFound
+	change.CreatedAt = timeNow
+	if err = insertStatusHistory(ctx, tx, change); err != nil {
+		return err
 	}
 
-	return nil
+	switch change.To {
+	case orderpb.OrderStatus_ORDER_STATUS_CANCELLED, orderpb.OrderStatus_ORDER_STATUS_REJECTED:
+		if err = releaseCoupons(ctx, tx, change.OrderID, timeNow); err != nil {
+			return err
+		}
+	}
+
+	if change.Refund != nil {
+		query, args, err = psql.Insert("refunds").
+			Columns("id", "order_id", "amount", "currency", "reason", "status", "created_at", "updated_at").
+			Values(uuid.New().String(), change.OrderID, change.Refund.Amount.MinorUnits, change.Refund.Amount.CurrencyCode, change.Refund.Reason, change.Refund.Status, timeNow, timeNow).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.Exec(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return outbox.Write(ctx, tx, change.OrderID, &eventspb.OrderStatusChanged{
+		OrderId:    change.OrderID,
+		BuyerId:    buyerID,
+		SellerId:   sellerID,
+		FromStatus: change.From,
+		ToStatus:   change.To,
+		ActorId:    change.ActorID,
+		Reason:     change.Reason,
+	})
+}
+
+// FetchStatusHistory returns every status change of an order, oldest first.
+func (r *orderRepository) FetchStatusHistory(ctx context.Context, orderID string) ([]models.StatusChange, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select("order_id", "from_status", "to_status", "actor_id", "actor_type", "reason", "created_at").
+		From("order_status_history").
+		Where(sq.Eq{"order_id": orderID}).
+		OrderBy("created_at ASC", "id ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []models.StatusChange{}
+	for rows.Next() {
+		change := models.StatusChange{}
+		err = rows.Scan(
+			&change.OrderID,
+			&change.From,
+			&change.To,
+			&change.ActorID,
+			&change.ActorType,
+			&change.Reason,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, change)
+	}
+
+	return result, rows.Err()
+}
+
+func insertStatusHistory(ctx context.Context, tx pgx.Tx, change models.StatusChange) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Insert("order_status_history").
+		Columns("id", "order_id", "from_status", "to_status", "actor_id", "actor_type", "reason", "created_at").
+		Values(uuid.New().String(), change.OrderID, change.From, change.To, change.ActorID, change.ActorType, change.Reason, change.CreatedAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, query, args...)
+	return err
+}
+
+func orderCreatedEvent(order models.Order) *eventspb.OrderCreated {
+	event := &eventspb.OrderCreated{
+		OrderId:    order.ID,
+		CheckoutId: order.CheckoutID,
+		BuyerId:    order.Buyer.ID,
+		SellerId:   order.Seller.ID,
+		TotalPrice: order.TotalPrice.Proto(),
+	}
+	for _, item := range order.Items {
+		event.Items = append(event.Items, &eventspb.OrderCreatedItem{
+			ProductId: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+	return event
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
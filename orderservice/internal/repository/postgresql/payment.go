@@ -0,0 +1,287 @@
+package postgresql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	"github.com/situmorangbastian/skyros/orderservice/internal/repository"
+	orderpb "github.com/situmorangbastian/skyros/proto/order"
+	paymentpb "github.com/situmorangbastian/skyros/proto/payment"
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+)
+
+var paymentIntentColumns = []string{
+	"id",
+	"order_id",
+	"buyer_id",
+	"provider",
+	"provider_reference",
+	"amount",
+	"currency",
+	"status",
+	"failure_reason",
+	"created_at",
+	"updated_at",
+}
+
+type paymentRepository struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewPaymentRepository(dbpool *pgxpool.Pool) repository.PaymentRepository {
+	return &paymentRepository{
+		dbpool: dbpool,
+	}
+}
+
+func (r *paymentRepository) StoreIntent(ctx context.Context, intent models.PaymentIntent) (models.PaymentIntent, error) {
+	timeNow := time.Now().UTC()
+	intent.ID = uuid.New().String()
+	intent.CreatedAt = timeNow
+	intent.UpdatedAt = timeNow
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Insert("payment_intents").
+		Columns(paymentIntentColumns...).
+		Values(
+			intent.ID,
+			intent.OrderID,
+			intent.BuyerID,
+			intent.Provider,
+			intent.ProviderReference,
+			intent.Amount.MinorUnits,
+			intent.Amount.CurrencyCode,
+			intent.Status,
+			intent.FailureReason,
+			intent.CreatedAt,
+			intent.UpdatedAt,
+		).
+		Suffix("ON CONFLICT (order_id) WHERE status IN (1, 2, 3) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	tag, err := r.dbpool.Exec(ctx, query, args...)
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.PaymentIntent{}, repository.ErrPaymentInProgress
+	}
+
+	return intent, nil
+}
+
+func (r *paymentRepository) GetIntent(ctx context.Context, ID string) (models.PaymentIntent, error) {
+	return r.getIntent(ctx, sq.Eq{"id": ID})
+}
+
+func (r *paymentRepository) GetIntentByReference(ctx context.Context, provider string, reference string) (models.PaymentIntent, error) {
+	return r.getIntent(ctx, sq.Eq{
+		"provider":           provider,
+		"provider_reference": reference,
+	})
+}
+
+func (r *paymentRepository) getIntent(ctx context.Context, where sq.Eq) (models.PaymentIntent, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select(paymentIntentColumns...).
+		From("payment_intents").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	intent, err := scanPaymentIntent(r.dbpool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.PaymentIntent{}, repository.ErrNotFound
+		}
+		return models.PaymentIntent{}, err
+	}
+
+	return intent, nil
+}
+
+// FetchIntents returns every payment intent of an order, oldest first.
+func (r *paymentRepository) FetchIntents(ctx context.Context, orderID string) ([]models.PaymentIntent, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select(paymentIntentColumns...).
+		From("payment_intents").
+		Where(sq.Eq{"order_id": orderID}).
+		OrderBy("created_at ASC", "id ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []models.PaymentIntent{}
+	for rows.Next() {
+		intent, err := scanPaymentIntent(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, intent)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *paymentRepository) UpdateIntent(ctx context.Context, update models.PaymentUpdate) (models.PaymentIntent, error) {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	timeNow := time.Now().UTC()
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	qBuilder := psql.Update("payment_intents").
+		Set("status", update.To).
+		Set("failure_reason", update.FailureReason).
+		Set("updated_at", timeNow).
+		Where(sq.Eq{
+			"id":     update.IntentID,
+			"status": update.From,
+		})
+	if update.ProviderReference != "" {
+		qBuilder = qBuilder.Set("provider_reference", update.ProviderReference)
+	}
+
+	query, args, err := qBuilder.Suffix("RETURNING " + strings.Join(paymentIntentColumns, ", ")).ToSql()
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	intent, err := scanPaymentIntent(tx.QueryRow(ctx, query, args...))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.PaymentIntent{}, repository.ErrNotFound
+		}
+		return models.PaymentIntent{}, err
+	}
+
+	if update.EventID != "" {
+		query, args, err = psql.Insert("payment_webhook_events").
+			Columns("provider", "event_id", "created_at").
+			Values(intent.Provider, update.EventID, timeNow).
+			Suffix("ON CONFLICT DO NOTHING").
+			ToSql()
+		if err != nil {
+			return models.PaymentIntent{}, err
+		}
+
+		tag, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return models.PaymentIntent{}, err
+		}
+		if tag.RowsAffected() == 0 {
+			return models.PaymentIntent{}, repository.ErrPaymentEventApplied
+		}
+	}
+
+	query, args, err = psql.Update("orders").
+		Set("payment_status", intent.Status).
+		Set("updated_at", timeNow).
+		Where(sq.Eq{"id": intent.OrderID}).
+		Suffix("RETURNING status").
+		ToSql()
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	var orderStatus orderpb.OrderStatus
+	if err = tx.QueryRow(ctx, query, args...).Scan(&orderStatus); err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	switch intent.Status {
+	case paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCEEDED:
+		err = settleOrder(ctx, tx, intent, orderStatus, timeNow)
+	case paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED:
+		query, args, err = psql.Update("refunds").
+			Set("status", models.RefundCompleted).
+			Set("updated_at", timeNow).
+			Where(sq.Eq{"order_id": intent.OrderID}).
+			ToSql()
+		if err == nil {
+			_, err = tx.Exec(ctx, query, args...)
+		}
+	}
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	return intent, nil
+}
+
+// settleOrder moves the pending order of a succeeded payment to paid. An order that was
+// cancelled or rejected while its payment was processing is owed a refund instead.
+func settleOrder(ctx context.Context, tx pgx.Tx, intent models.PaymentIntent, orderStatus orderpb.OrderStatus, timeNow time.Time) error {
+	switch orderStatus {
+	case orderpb.OrderStatus_ORDER_STATUS_PENDING:
+		return patchStatus(ctx, tx, models.StatusChange{
+			OrderID:   intent.OrderID,
+			From:      orderStatus,
+			To:        orderpb.OrderStatus_ORDER_STATUS_PAID,
+			ActorID:   intent.BuyerID,
+			ActorType: auth.UserBuyerType,
+			Reason:    "payment succeeded",
+		}, timeNow)
+	case orderpb.OrderStatus_ORDER_STATUS_CANCELLED, orderpb.OrderStatus_ORDER_STATUS_REJECTED:
+		psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+		query, args, err := psql.Insert("refunds").
+			Columns("id", "order_id", "amount", "currency", "reason", "status", "created_at", "updated_at").
+			Values(uuid.New().String(), intent.OrderID, intent.Amount.MinorUnits, intent.Amount.CurrencyCode, "payment succeeded after the order was closed", models.RefundPending, timeNow, timeNow).
+			Suffix("ON CONFLICT (order_id) DO NOTHING").
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, query, args...)
+		return err
+	}
+
+	return nil
+}
+
+func scanPaymentIntent(row pgx.Row) (models.PaymentIntent, error) {
+	intent := models.PaymentIntent{}
+	err := row.Scan(
+		&intent.ID,
+		&intent.OrderID,
+		&intent.BuyerID,
+		&intent.Provider,
+		&intent.ProviderReference,
+		&intent.Amount.MinorUnits,
+		&intent.Amount.CurrencyCode,
+		&intent.Status,
+		&intent.FailureReason,
+		&intent.CreatedAt,
+		&intent.UpdatedAt,
+	)
+	return intent, err
+}
@@ -0,0 +1,196 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	"github.com/situmorangbastian/skyros/orderservice/internal/repository"
+)
+
+var couponColumns = []string{
+	"id",
+	"code",
+	"seller_id",
+	"discount_type",
+	"percent_off",
+	"amount_off",
+	"min_order_value",
+	"currency",
+	"starts_at",
+	"ends_at",
+	"max_redemptions",
+	"max_redemptions_per_buyer",
+	"redemptions",
+	"created_at",
+}
+
+type promotionRepository struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewPromotionRepository(dbpool *pgxpool.Pool) repository.PromotionRepository {
+	return &promotionRepository{
+		dbpool: dbpool,
+	}
+}
+
+func (r *promotionRepository) StoreCoupon(ctx context.Context, coupon models.Coupon) (models.Coupon, error) {
+	timeNow := time.Now().UTC()
+	coupon.ID = uuid.New().String()
+	coupon.CreatedAt = timeNow
+
+	// Amount off and minimum order value share the coupon's currency.
+	currency := coupon.AmountOff.CurrencyCode
+	if currency == "" {
+		currency = coupon.MinOrderValue.CurrencyCode
+	}
+
+	var endsAt *time.Time
+	if !coupon.EndsAt.IsZero() {
+		endsAt = &coupon.EndsAt
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Insert("coupons").
+		Columns(
+			"id",
+			"code",
+			"seller_id",
+			"discount_type",
+			"percent_off",
+			"amount_off",
+			"min_order_value",
+			"currency",
+			"starts_at",
+			"ends_at",
+			"max_redemptions",
+			"max_redemptions_per_buyer",
+			"created_at",
+			"updated_at",
+		).
+		Values(
+			coupon.ID,
+			coupon.Code,
+			coupon.SellerID,
+			coupon.DiscountType,
+			coupon.PercentOff,
+			coupon.AmountOff.MinorUnits,
+			coupon.MinOrderValue.MinorUnits,
+			currency,
+			coupon.StartsAt,
+			endsAt,
+			coupon.MaxRedemptions,
+			coupon.MaxRedemptionsPerBuyer,
+			timeNow,
+			timeNow,
+		).
+		Suffix("ON CONFLICT (code) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return models.Coupon{}, err
+	}
+
+	result, err := r.dbpool.Exec(ctx, query, args...)
+	if err != nil {
+		return models.Coupon{}, err
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.Coupon{}, repository.ErrCouponExists
+	}
+
+	return coupon, nil
+}
+
+func (r *promotionRepository) FetchCoupons(ctx context.Context, sellerID string) ([]models.Coupon, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select(couponColumns...).
+		From("coupons").
+		Where(sq.Eq{"seller_id": sellerID}).
+		OrderBy("created_at DESC", "id DESC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	coupons := []models.Coupon{}
+	for rows.Next() {
+		coupon, err := scanCoupon(rows)
+		if err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, coupon)
+	}
+
+	return coupons, rows.Err()
+}
+
+func (r *promotionRepository) GetCouponByCode(ctx context.Context, code string) (models.Coupon, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select(couponColumns...).
+		From("coupons").
+		Where(sq.Eq{"code": code}).
+		ToSql()
+	if err != nil {
+		return models.Coupon{}, err
+	}
+
+	coupon, err := scanCoupon(r.dbpool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Coupon{}, repository.ErrNotFound
+		}
+		return models.Coupon{}, err
+	}
+
+	return coupon, nil
+}
+
+func scanCoupon(row pgx.Row) (models.Coupon, error) {
+	coupon := models.Coupon{}
+	var currency string
+	var endsAt *time.Time
+	err := row.Scan(
+		&coupon.ID,
+		&coupon.Code,
+		&coupon.SellerID,
+		&coupon.DiscountType,
+		&coupon.PercentOff,
+		&coupon.AmountOff.MinorUnits,
+		&coupon.MinOrderValue.MinorUnits,
+		&currency,
+		&coupon.StartsAt,
+		&endsAt,
+		&coupon.MaxRedemptions,
+		&coupon.MaxRedemptionsPerBuyer,
+		&coupon.Redemptions,
+		&coupon.CreatedAt,
+	)
+	if err != nil {
+		return models.Coupon{}, err
+	}
+
+	if coupon.AmountOff.MinorUnits != 0 {
+		coupon.AmountOff.CurrencyCode = currency
+	}
+	if coupon.MinOrderValue.MinorUnits != 0 {
+		coupon.MinOrderValue.CurrencyCode = currency
+	}
+	if endsAt != nil {
+		coupon.EndsAt = *endsAt
+	}
+
+	return coupon, nil
+}
@@ -0,0 +1,449 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	"github.com/situmorangbastian/skyros/orderservice/internal/repository"
+	orderpb "github.com/situmorangbastian/skyros/proto/order"
+	shipmentpb "github.com/situmorangbastian/skyros/proto/shipment"
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+)
+
+var shipmentColumns = []string{
+	"id",
+	"order_id",
+	"carrier",
+	"tracking_number",
+	"source_address",
+	"destination_address",
+	"status",
+	"created_at",
+	"updated_at",
+}
+
+type shipmentRepository struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewShipmentRepository(dbpool *pgxpool.Pool) repository.ShipmentRepository {
+	return &shipmentRepository{
+		dbpool: dbpool,
+	}
+}
+
+func (r *shipmentRepository) StoreShipment(ctx context.Context, shipment models.Shipment, actor auth.Claims) (models.Shipment, error) {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return models.Shipment{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// The order row lock serializes concurrent shipments of the same order.
+	orderStatus, err := lockOrder(ctx, tx, shipment.OrderID)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+	if orderStatus != orderpb.OrderStatus_ORDER_STATUS_ACCEPTED {
+		return models.Shipment{}, repository.ErrNotFound
+	}
+
+	remaining, err := unshippedQuantities(ctx, tx, shipment.OrderID)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	for _, item := range shipment.Items {
+		if item.Quantity > remaining[item.ProductID] {
+			return models.Shipment{}, repository.ErrShipmentExceedsOrder
+		}
+		remaining[item.ProductID] -= item.Quantity
+	}
+
+	timeNow := time.Now().UTC()
+	shipment.ID = uuid.New().String()
+	shipment.Status = shipmentpb.ShipmentStatus_SHIPMENT_STATUS_LABEL_CREATED
+	shipment.Events = []models.TrackingEvent{}
+	shipment.CreatedAt = timeNow
+	shipment.UpdatedAt = timeNow
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Insert("shipments").
+		Columns(shipmentColumns...).
+		Values(
+			shipment.ID,
+			shipment.OrderID,
+			shipment.Carrier,
+			shipment.TrackingNumber,
+			shipment.SourceAddress,
+			shipment.DestinationAddress,
+			shipment.Status,
+			shipment.CreatedAt,
+			shipment.UpdatedAt,
+		).
+		Suffix("ON CONFLICT (carrier, tracking_number) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	tag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.Shipment{}, repository.ErrShipmentExists
+	}
+
+	itemsBuilder := psql.Insert("shipment_items").Columns("shipment_id", "product_id", "quantity")
+	for _, item := range shipment.Items {
+		itemsBuilder = itemsBuilder.Values(shipment.ID, item.ProductID, item.Quantity)
+	}
+
+	query, args, err = itemsBuilder.ToSql()
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return models.Shipment{}, err
+	}
+
+	shipped := true
+	for _, quantity := range remaining {
+		if quantity > 0 {
+			shipped = false
+			break
+		}
+	}
+
+	if shipped {
+		err = patchStatus(ctx, tx, models.StatusChange{
+			OrderID:   shipment.OrderID,
+			From:      orderStatus,
+			To:        orderpb.OrderStatus_ORDER_STATUS_SHIPPED,
+			ActorID:   actor.ID,
+			ActorType: actor.Type,
+		}, timeNow)
+		if err != nil {
+			return models.Shipment{}, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return models.Shipment{}, err
+	}
+
+	return shipment, nil
+}
+
+func (r *shipmentRepository) GetShipment(ctx context.Context, ID string) (models.Shipment, error) {
+	result, err := r.fetch(ctx, sq.Eq{"id": ID})
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	if len(result) == 0 {
+		return models.Shipment{}, repository.ErrNotFound
+	}
+
+	return result[0], nil
+}
+
+// FetchShipments returns the shipments of an order, oldest first.
+func (r *shipmentRepository) FetchShipments(ctx context.Context, orderID string) ([]models.Shipment, error) {
+	return r.fetch(ctx, sq.Eq{"order_id": orderID})
+}
+
+func (r *shipmentRepository) fetch(ctx context.Context, where sq.Eq) ([]models.Shipment, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select(shipmentColumns...).
+		From("shipments").
+		Where(where).
+		OrderBy("created_at ASC", "id ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []models.Shipment{}
+	index := map[string]int{}
+	for rows.Next() {
+		shipment := models.Shipment{
+			Items:  []models.ShipmentItem{},
+			Events: []models.TrackingEvent{},
+		}
+		err = rows.Scan(
+			&shipment.ID,
+			&shipment.OrderID,
+			&shipment.Carrier,
+			&shipment.TrackingNumber,
+			&shipment.SourceAddress,
+			&shipment.DestinationAddress,
+			&shipment.Status,
+			&shipment.CreatedAt,
+			&shipment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		index[shipment.ID] = len(result)
+		result = append(result, shipment)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return result, nil
+	}
+
+	shipmentIds := make([]string, 0, len(result))
+	for _, shipment := range result {
+		shipmentIds = append(shipmentIds, shipment.ID)
+	}
+
+	query, args, err = psql.Select("shipment_id", "product_id", "quantity").
+		From("shipment_items").
+		Where(sq.Eq{"shipment_id": shipmentIds}).
+		OrderBy("product_id ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	itemRows, err := r.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var shipmentID string
+		item := models.ShipmentItem{}
+		if err = itemRows.Scan(&shipmentID, &item.ProductID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		result[index[shipmentID]].Items = append(result[index[shipmentID]].Items, item)
+	}
+	if err = itemRows.Err(); err != nil {
+		return nil, err
+	}
+
+	query, args, err = psql.Select("shipment_id", "status", "location", "description", "occurred_at", "created_at").
+		From("shipment_events").
+		Where(sq.Eq{"shipment_id": shipmentIds}).
+		OrderBy("occurred_at ASC", "created_at ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	eventRows, err := r.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer eventRows.Close()
+
+	for eventRows.Next() {
+		var shipmentID string
+		event := models.TrackingEvent{}
+		err = eventRows.Scan(&shipmentID, &event.Status, &event.Location, &event.Description, &event.OccurredAt, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		result[index[shipmentID]].Events = append(result[index[shipmentID]].Events, event)
+	}
+
+	return result, eventRows.Err()
+}
+
+func (r *shipmentRepository) AddTrackingEvent(ctx context.Context, shipmentID string, from shipmentpb.ShipmentStatus, event models.TrackingEvent, actor auth.Claims) error {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select("order_id").
+		From("shipments").
+		Where(sq.Eq{"id": shipmentID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var orderID string
+	if err = tx.QueryRow(ctx, query, args...).Scan(&orderID); err != nil {
+		if err == pgx.ErrNoRows {
+			return repository.ErrNotFound
+		}
+		return err
+	}
+
+	// Locking the order first makes the last of several concurrent deliveries see the
+	// others and complete the order.
+	orderStatus, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+
+	timeNow := time.Now().UTC()
+	query, args, err = psql.Update("shipments").
+		Set("status", event.Status).
+		Set("updated_at", timeNow).
+		Where(sq.Eq{
+			"id":     shipmentID,
+			"status": from,
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	query, args, err = psql.Insert("shipment_events").
+		Columns("id", "shipment_id", "status", "location", "description", "occurred_at", "created_at").
+		Values(uuid.New().String(), shipmentID, event.Status, event.Location, event.Description, event.OccurredAt, timeNow).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+
+	if event.Status == shipmentpb.ShipmentStatus_SHIPMENT_STATUS_DELIVERED && orderStatus == orderpb.OrderStatus_ORDER_STATUS_SHIPPED {
+		query, args, err = psql.Select("COUNT(*)").
+			From("shipments").
+			Where(sq.Eq{"order_id": orderID}).
+			Where(sq.NotEq{"status": shipmentpb.ShipmentStatus_SHIPMENT_STATUS_DELIVERED}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		var undelivered int64
+		if err = tx.QueryRow(ctx, query, args...).Scan(&undelivered); err != nil {
+			return err
+		}
+
+		if undelivered == 0 {
+			err = patchStatus(ctx, tx, models.StatusChange{
+				OrderID:   orderID,
+				From:      orderStatus,
+				To:        orderpb.OrderStatus_ORDER_STATUS_DELIVERED,
+				ActorID:   actor.ID,
+				ActorType: actor.Type,
+			}, timeNow)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockOrder locks the order row for the rest of tx and returns its status.
+func lockOrder(ctx context.Context, tx pgx.Tx, orderID string) (orderpb.OrderStatus, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select("status").
+		From("orders").
+		Where(sq.Eq{"id": orderID}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED, err
+	}
+
+	var orderStatus orderpb.OrderStatus
+	if err = tx.QueryRow(ctx, query, args...).Scan(&orderStatus); err != nil {
+		if err == pgx.ErrNoRows {
+			return orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED, repository.ErrNotFound
+		}
+		return orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED, err
+	}
+
+	return orderStatus, nil
+}
+
+// unshippedQuantities returns, per product of an order, the quantity not in any shipment yet.
+func unshippedQuantities(ctx context.Context, tx pgx.Tx, orderID string) (map[string]int64, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	result := map[string]int64{}
+
+	query, args, err := psql.Select("product_id", "SUM(quantity)::BIGINT").
+		From("orders_products").
+		Where(sq.Eq{"order_id": orderID}).
+		GroupBy("product_id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = sumQuantities(ctx, tx, query, args, result, 1); err != nil {
+		return nil, err
+	}
+
+	query, args, err = psql.Select("si.product_id", "SUM(si.quantity)::BIGINT").
+		From("shipment_items si").
+		Join("shipments s ON s.id = si.shipment_id").
+		Where(sq.Eq{"s.order_id": orderID}).
+		GroupBy("si.product_id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = sumQuantities(ctx, tx, query, args, result, -1); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// sumQuantities adds sign times the quantity of every (product_id, quantity) row of query to result.
+func sumQuantities(ctx context.Context, tx pgx.Tx, query string, args []any, result map[string]int64, sign int64) error {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID string
+		var quantity int64
+		if err = rows.Scan(&productID, &quantity); err != nil {
+			return err
+		}
+		result[productID] += sign * quantity
+	}
+
+	return rows.Err()
+}
@@ -3,16 +3,93 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	shipmentpb "github.com/situmorangbastian/skyros/proto/shipment"
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
 )
 
 var (
 	ErrNotFound = errors.New("not found")
+	// ErrCouponExists is returned when storing a coupon whose code is taken.
+	ErrCouponExists = errors.New("coupon code already exists")
+	// ErrCouponLimitReached is returned by StoreCheckout when redeeming a coupon would
+	// exceed its global or per buyer limit.
+	ErrCouponLimitReached = errors.New("coupon usage limit reached")
+	// ErrPaymentInProgress is returned when storing a payment intent for an order that
+	// already has one in progress or succeeded.
+	ErrPaymentInProgress = errors.New("order already has a payment in progress")
+	// ErrPaymentEventApplied is returned by UpdateIntent for a webhook event that was
+	// already applied.
+	ErrPaymentEventApplied = errors.New("payment event already applied")
+	// ErrShipmentExists is returned when storing a shipment whose carrier tracking
+	// number is taken.
+	ErrShipmentExists = errors.New("tracking number already exists for the carrier")
+	// ErrShipmentExceedsOrder is returned when a shipment holds more of a product than
+	// is left to ship in its order.
+	ErrShipmentExceedsOrder = errors.New("shipment items exceed the items left to ship")
 )
 
 type OrderRepository interface {
-	Store(ctx context.Context, order models.Order) (models.Order, error)
+	// StoreCheckout also redeems the coupons in the orders' discounts, failing with
+	// ErrCouponLimitReached when a coupon is used up.
+	StoreCheckout(ctx context.Context, checkout models.Checkout) (models.Checkout, error)
 	Fetch(ctx context.Context, filter models.Filter) ([]models.Order, error)
-	PatchStatus(ctx context.Context, ID string, status int) error
+	// PatchStatus applies change only if the order is still in change.From, recording
+	// it in the order history together with any refund. It returns ErrNotFound when no
+	// such order exists in that status.
+	PatchStatus(ctx context.Context, change models.StatusChange) error
+	FetchStatusHistory(ctx context.Context, orderID string) ([]models.StatusChange, error)
+}
+
+type PromotionRepository interface {
+	StoreCoupon(ctx context.Context, coupon models.Coupon) (models.Coupon, error)
+	FetchCoupons(ctx context.Context, sellerID string) ([]models.Coupon, error)
+	// GetCouponByCode returns ErrNotFound when no coupon has code.
+	GetCouponByCode(ctx context.Context, code string) (models.Coupon, error)
+}
+
+// CartRepository stores one cart per buyer. An expired cart reads as empty and is
+// discarded on the next change.
+type CartRepository interface {
+	Get(ctx context.Context, buyerID string) (models.Cart, error)
+	// AddItem adds item.Quantity to the buyer's item of the product, creating it if needed.
+	AddItem(ctx context.Context, buyerID string, item models.CartItem, expiresAt time.Time) error
+	// UpdateItem sets the quantity of an item, returning ErrNotFound when it is not in the cart.
+	UpdateItem(ctx context.Context, buyerID string, item models.CartItem, expiresAt time.Time) error
+	// RemoveItem returns ErrNotFound when the product is not in the cart.
+	RemoveItem(ctx context.Context, buyerID string, productID string, expiresAt time.Time) error
+	Delete(ctx context.Context, buyerID string) error
+}
+
+type PaymentRepository interface {
+	// StoreIntent returns ErrPaymentInProgress when the order has a payment that is in
+	// progress or succeeded.
+	StoreIntent(ctx context.Context, intent models.PaymentIntent) (models.PaymentIntent, error)
+	// GetIntent returns ErrNotFound when no intent has ID.
+	GetIntent(ctx context.Context, ID string) (models.PaymentIntent, error)
+	// GetIntentByReference returns ErrNotFound when no intent of provider has reference.
+	GetIntentByReference(ctx context.Context, provider string, reference string) (models.PaymentIntent, error)
+	FetchIntents(ctx context.Context, orderID string) ([]models.PaymentIntent, error)
+	// UpdateIntent applies update only if the intent is still in update.From and carries
+	// the new status over to the order: a succeeded payment moves a pending order to
+	// paid, or owes a refund when the order was closed meanwhile, and a refunded
+	// payment completes the order's refund. It returns ErrNotFound when no such intent
+	// exists in that status.
+	UpdateIntent(ctx context.Context, update models.PaymentUpdate) (models.PaymentIntent, error)
+}
+
+type ShipmentRepository interface {
+	// StoreShipment saves a shipment of an accepted order and moves the order to shipped
+	// on behalf of actor once all of its items are shipped. It returns ErrNotFound when
+	// the order is not accepted, ErrShipmentExceedsOrder and ErrShipmentExists.
+	StoreShipment(ctx context.Context, shipment models.Shipment, actor auth.Claims) (models.Shipment, error)
+	// GetShipment returns ErrNotFound when no shipment has ID.
+	GetShipment(ctx context.Context, ID string) (models.Shipment, error)
+	FetchShipments(ctx context.Context, orderID string) ([]models.Shipment, error)
+	// AddTrackingEvent records event only if the shipment is still in status from, and
+	// moves a shipped order to delivered on behalf of actor once all of its shipments are
+	// delivered. It returns ErrNotFound when the shipment is no longer in from.
+	AddTrackingEvent(ctx context.Context, shipmentID string, from shipmentpb.ShipmentStatus, event models.TrackingEvent, actor auth.Claims) error
 }
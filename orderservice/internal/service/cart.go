@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	"github.com/situmorangbastian/skyros/orderservice/internal/usecase"
+	cartpb "github.com/situmorangbastian/skyros/proto/cart"
+	orderpb "github.com/situmorangbastian/skyros/proto/order"
+	"github.com/situmorangbastian/skyros/serviceutils"
+)
+
+type cartService struct {
+	usecase   usecase.CartUsecase
+	validator serviceutils.CustomValidator
+}
+
+func NewCartService(usecase usecase.CartUsecase, validator serviceutils.CustomValidator) cartpb.CartServiceServer {
+	return &cartService{
+		usecase:   usecase,
+		validator: validator,
+	}
+}
+
+func (s *cartService) GetCart(ctx context.Context, request *cartpb.GetCartRequest) (*cartpb.Cart, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.cart.GetCart").Logger()
+	log.Info().Msg("request received")
+
+	res, err := s.usecase.Get(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Get")
+		return nil, err
+	}
+
+	return toCartProto(res), nil
+}
+
+func (s *cartService) AddCartItem(ctx context.Context, request *cartpb.AddCartItemRequest) (*cartpb.Cart, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.cart.AddCartItem").Logger()
+	log.Info().Msg("request received")
+
+	item := models.CartItem{
+		ProductID: request.GetProductId(),
+		Quantity:  request.GetQuantity(),
+	}
+	if err := s.validator.Validate(item); err != nil {
+		return nil, err
+	}
+
+	res, err := s.usecase.AddItem(ctx, item)
+	if err != nil {
+		log.Error().Err(err).Msg("failed AddItem")
+		return nil, err
+	}
+
+	return toCartProto(res), nil
+}
+
+func (s *cartService) UpdateCartItem(ctx context.Context, request *cartpb.UpdateCartItemRequest) (*cartpb.Cart, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.cart.UpdateCartItem").Logger()
+	log.Info().Msg("request received")
+
+	item := models.CartItem{
+		ProductID: request.GetProductId(),
+		Quantity:  request.GetQuantity(),
+	}
+	if err := s.validator.Validate(item); err != nil {
+		return nil, err
+	}
+
+	res, err := s.usecase.UpdateItem(ctx, item)
+	if err != nil {
+		log.Error().Err(err).Msg("failed UpdateItem")
+		return nil, err
+	}
+
+	return toCartProto(res), nil
+}
+
+func (s *cartService) RemoveCartItem(ctx context.Context, request *cartpb.RemoveCartItemRequest) (*cartpb.Cart, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.cart.RemoveCartItem").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetProductId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "product_id is required")
+	}
+
+	res, err := s.usecase.RemoveItem(ctx, request.GetProductId())
+	if err != nil {
+		log.Error().Err(err).Msg("failed RemoveItem")
+		return nil, err
+	}
+
+	return toCartProto(res), nil
+}
+
+func (s *cartService) Checkout(ctx context.Context, request *cartpb.CheckoutRequest) (*orderpb.CreateOrderResponse, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.cart.Checkout").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetAddressId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "address_id is required")
+	}
+
+	res, err := s.usecase.Checkout(ctx, models.Order{
+		Description: request.GetDescription(),
+		AddressID:   request.GetAddressId(),
+		CouponCode:  request.GetCouponCode(),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed Checkout")
+		return nil, err
+	}
+
+	return toCreateOrderResponse(res), nil
+}
+
+func toCartProto(cart models.Cart) *cartpb.Cart {
+	result := &cartpb.Cart{
+		Items: []*cartpb.CartItem{},
+	}
+
+	for _, item := range cart.Items {
+		cartItem := &cartpb.CartItem{
+			ProductId:      item.ProductID,
+			Quantity:       item.Quantity,
+			Name:           item.Product.Name,
+			SellerId:       item.Product.Seller.ID,
+			AddedUnitPrice: item.AddedPrice.Proto(),
+			Warnings:       item.Warnings,
+		}
+		if !item.Product.Price.IsZero() {
+			cartItem.UnitPrice = item.Product.Price.Proto()
+			cartItem.Subtotal = item.Subtotal.Proto()
+		}
+		result.Items = append(result.Items, cartItem)
+	}
+
+	if !cart.Total.IsZero() {
+		result.Total = cart.Total.Proto()
+	}
+	if !cart.ExpiresAt.IsZero() {
+		result.ExpiresAt = timestamppb.New(cart.ExpiresAt)
+	}
+
+	return result
+}
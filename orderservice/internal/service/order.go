@@ -2,40 +2,49 @@ package service
 
 import (
 	"context"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/situmorangbastian/skyros/orderservice/internal/models"
 	"github.com/situmorangbastian/skyros/orderservice/internal/usecase"
 	orderpb "github.com/situmorangbastian/skyros/proto/order"
 	userpb "github.com/situmorangbastian/skyros/proto/user"
 	"github.com/situmorangbastian/skyros/serviceutils"
+	"github.com/situmorangbastian/skyros/serviceutils/money"
+	"github.com/situmorangbastian/skyros/serviceutils/pagination"
 )
 
 type service struct {
-	usecase   usecase.OrderUsecase
-	validator serviceutils.CustomValidator
-	logger    zerolog.Logger
+	usecase    usecase.OrderUsecase
+	validator  serviceutils.CustomValidator
+	pageTokens *pagination.Codec
+	logger     zerolog.Logger
 }
 
-func NewOrderService(usecase usecase.OrderUsecase, validator serviceutils.CustomValidator, logger zerolog.Logger) orderpb.OrderServiceServer {
+func NewOrderService(usecase usecase.OrderUsecase, validator serviceutils.CustomValidator, pageTokens *pagination.Codec, logger zerolog.Logger) orderpb.OrderServiceServer {
 	return &service{
-		usecase:   usecase,
-		validator: validator,
-		logger:    logger,
+		usecase:    usecase,
+		validator:  validator,
+		pageTokens: pageTokens,
+		logger:     logger,
 	}
 }
 
-func (s *service) CreateOrder(ctx context.Context, request *orderpb.CreateOrderRequest) (*orderpb.Order, error) {
+func (s *service) CreateOrder(ctx context.Context, request *orderpb.CreateOrderRequest) (*orderpb.CreateOrderResponse, error) {
 	log := zerolog.Ctx(ctx)
 	log.With().Str("func", "internal.service.order.CreateOrder").Logger()
 	log.Info().Msg("request received")
 
 	req := models.Order{
-		Description:        request.GetDescription(),
-		DestinationAddress: request.GetDestinationAddress(),
+		Description: request.GetDescription(),
+		AddressID:   request.GetAddressId(),
+		CouponCode:  request.GetCouponCode(),
 	}
 
 	if request.GetItems() == nil || (request.GetItems() != nil && len(request.GetItems()) == 0) {
@@ -62,40 +71,20 @@ func (s *service) CreateOrder(ctx context.Context, request *orderpb.CreateOrderR
 		return nil, err
 	}
 
-	return &orderpb.Order{
-		Id:                 res.ID,
-		Description:        res.Description,
-		SourceAddress:      res.SourceAddress,
-		DestinationAddress: res.DestinationAddress,
-		TotalPrice:         res.TotalPrice,
-		Status: func() string {
-			status := "pending"
-			if res.Status == 1 {
-				status = "accepted"
-			}
-			return status
-		}(),
-		Seller: &userpb.User{
-			Name:    res.Seller.Name,
-			Address: res.Seller.Address,
-		},
-		Buyer: &userpb.User{
-			Name:    res.Seller.Name,
-			Address: res.Seller.Address,
-		},
-		Items: func() []*orderpb.OrderProduct {
-			items := []*orderpb.OrderProduct{}
-			for _, item := range res.Items {
-				items = append(items, &orderpb.OrderProduct{
-					ProductId: item.ProductID,
-					Quantity:  item.Quantity,
-				})
-			}
-			return items
-		}(),
-		CreatedAt: res.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt: res.UpdatedAt.Format("2006-01-02 15:04:05"),
-	}, nil
+	return toCreateOrderResponse(res), nil
+}
+
+func toCreateOrderResponse(checkout models.Checkout) *orderpb.CreateOrderResponse {
+	orders := []*orderpb.Order{}
+	for _, order := range checkout.Orders {
+		orders = append(orders, toOrderProto(order))
+	}
+
+	return &orderpb.CreateOrderResponse{
+		CheckoutId: checkout.ID,
+		TotalPrice: checkout.TotalPrice.Proto(),
+		Orders:     orders,
+	}
 }
 
 func (s *service) GetOrder(ctx context.Context, request *orderpb.GetOrderRequest) (*orderpb.Order, error) {
@@ -109,40 +98,7 @@ func (s *service) GetOrder(ctx context.Context, request *orderpb.GetOrderRequest
 		return nil, err
 	}
 
-	return &orderpb.Order{
-		Id:                 res.ID,
-		Description:        res.Description,
-		SourceAddress:      res.SourceAddress,
-		DestinationAddress: res.DestinationAddress,
-		TotalPrice:         res.TotalPrice,
-		Status: func() string {
-			status := "pending"
-			if res.Status == 1 {
-				status = "accepted"
-			}
-			return status
-		}(),
-		Seller: &userpb.User{
-			Name:    res.Seller.Name,
-			Address: res.Seller.Address,
-		},
-		Buyer: &userpb.User{
-			Name:    res.Seller.Name,
-			Address: res.Seller.Address,
-		},
-		Items: func() []*orderpb.OrderProduct {
-			items := []*orderpb.OrderProduct{}
-			for _, item := range res.Items {
-				items = append(items, &orderpb.OrderProduct{
-					ProductId: item.ProductID,
-					Quantity:  item.Quantity,
-				})
-			}
-			return items
-		}(),
-		CreatedAt: res.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt: res.UpdatedAt.Format("2006-01-02 15:04:05"),
-	}, nil
+	return toOrderProto(res), nil
 }
 
 func (s *service) GetOrders(ctx context.Context, request *orderpb.GetOrdersRequest) (*orderpb.GetOrdersResponse, error) {
@@ -150,60 +106,258 @@ func (s *service) GetOrders(ctx context.Context, request *orderpb.GetOrdersReque
 	log.With().Str("func", "internal.service.order.GetOrders").Logger()
 	log.Info().Msg("request received")
 
-	limit := request.GetLimit()
-	if limit == 0 {
-		limit = 20
+	pageSize, err := pagination.PageSize(request.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	orderSort, ok := orderSorts[request.GetSort()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid sort")
 	}
 
-	orders, err := s.usecase.Fetch(ctx, models.Filter{
-		PageSize: int(limit),
-		Page:     int(request.GetOffset()),
-		Search:   request.GetSearch(),
-	})
+	if request.GetCurrencyCode() != "" && !money.ValidCurrencyCode(request.GetCurrencyCode()) {
+		return nil, status.Error(codes.InvalidArgument, "invalid currency_code")
+	}
+
+	// Totals in different currencies cannot be compared.
+	if (orderSort == models.OrderSortTotalPriceAsc || orderSort == models.OrderSortTotalPriceDesc) && request.GetCurrencyCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "currency_code is required to sort by total price")
+	}
+
+	// One extra order is fetched to tell whether there is a next page.
+	filter := models.Filter{
+		PageSize:     pageSize + 1,
+		Search:       strings.TrimSpace(request.GetSearch()),
+		ProductID:    request.GetProductId(),
+		CurrencyCode: request.GetCurrencyCode(),
+		Sort:         orderSort,
+	}
+
+	statuses := make([]string, 0, len(request.GetStatuses()))
+	for _, orderStatus := range request.GetStatuses() {
+		if _, ok := orderpb.OrderStatus_name[int32(orderStatus)]; !ok || orderStatus == orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid status %d", orderStatus)
+		}
+		filter.Statuses = append(filter.Statuses, orderStatus)
+		statuses = append(statuses, orderStatus.String())
+	}
+
+	if request.GetCreatedAfter() != nil {
+		filter.CreatedAfter = request.GetCreatedAfter().AsTime()
+	}
+	if request.GetCreatedBefore() != nil {
+		filter.CreatedBefore = request.GetCreatedBefore().AsTime()
+	}
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() && !filter.CreatedAfter.Before(filter.CreatedBefore) {
+		return nil, status.Error(codes.InvalidArgument, "created_after must be before created_before")
+	}
+
+	filterHash := pagination.FilterHash(
+		filter.Search,
+		filter.ProductID,
+		strings.Join(statuses, ","),
+		formatTime(filter.CreatedAfter),
+		formatTime(filter.CreatedBefore),
+		filter.CurrencyCode,
+		strconv.Itoa(int(filter.Sort)),
+	)
+	if request.GetPageToken() != "" {
+		cursor, err := s.pageTokens.Decode(request.GetPageToken(), filterHash)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = &cursor
+	}
+
+	orders, err := s.usecase.Fetch(ctx, filter)
 	if err != nil {
 		log.Error().Err(err).Msg("failed Fetch")
 		return nil, err
 	}
 
+	var nextPageToken string
+	if len(orders) > pageSize {
+		orders = orders[:pageSize]
+		last := orders[pageSize-1]
+		cursor := pagination.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+			Filter:    filterHash,
+		}
+		if filter.Sort == models.OrderSortTotalPriceAsc || filter.Sort == models.OrderSortTotalPriceDesc {
+			cursor.Key = strconv.FormatInt(last.TotalPrice.MinorUnits, 10)
+		}
+
+		nextPageToken, err = s.pageTokens.Encode(cursor)
+		if err != nil {
+			log.Error().Err(err).Msg("failed encode page token")
+			return nil, status.Error(codes.Internal, "Internal Server Error")
+		}
+	}
+
 	result := []*orderpb.Order{}
 	for _, order := range orders {
-		result = append(result, &orderpb.Order{
-			Id:                 order.ID,
-			Description:        order.Description,
-			SourceAddress:      order.SourceAddress,
-			DestinationAddress: order.DestinationAddress,
-			TotalPrice:         order.TotalPrice,
-			Status: func() string {
-				status := "pending"
-				if order.Status == 1 {
-					status = "accepted"
-				}
-				return status
-			}(),
-			Seller: &userpb.User{
-				Name:    order.Seller.Name,
-				Address: order.Seller.Address,
-			},
-			Buyer: &userpb.User{
-				Name:    order.Seller.Name,
-				Address: order.Seller.Address,
-			},
-			Items: func() []*orderpb.OrderProduct {
-				items := []*orderpb.OrderProduct{}
-				for _, item := range order.Items {
-					items = append(items, &orderpb.OrderProduct{
-						ProductId: item.ProductID,
-						Quantity:  item.Quantity,
-					})
-				}
-				return items
-			}(),
-			CreatedAt: order.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt: order.UpdatedAt.Format("2006-01-02 15:04:05"),
-		})
+		result = append(result, toOrderProto(order))
 	}
 
 	return &orderpb.GetOrdersResponse{
-		Result: result,
+		Result:        result,
+		NextPageToken: nextPageToken,
 	}, nil
 }
+
+var orderSorts = map[orderpb.OrderSort]models.OrderSort{
+	orderpb.OrderSort_ORDER_SORT_UNSPECIFIED:      models.OrderSortNewest,
+	orderpb.OrderSort_ORDER_SORT_NEWEST:           models.OrderSortNewest,
+	orderpb.OrderSort_ORDER_SORT_OLDEST:           models.OrderSortOldest,
+	orderpb.OrderSort_ORDER_SORT_TOTAL_PRICE_ASC:  models.OrderSortTotalPriceAsc,
+	orderpb.OrderSort_ORDER_SORT_TOTAL_PRICE_DESC: models.OrderSortTotalPriceDesc,
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func (s *service) UpdateOrderStatus(ctx context.Context, request *orderpb.UpdateOrderStatusRequest) (*orderpb.Order, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.order.UpdateOrderStatus").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	if request.GetStatus() == orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+
+	res, err := s.usecase.PatchStatus(ctx, request.GetOrderId(), request.GetStatus())
+	if err != nil {
+		log.Error().Err(err).Msg("failed PatchStatus")
+		return nil, err
+	}
+
+	return toOrderProto(res), nil
+}
+
+func (s *service) CancelOrder(ctx context.Context, request *orderpb.CancelOrderRequest) (*orderpb.Order, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.order.CancelOrder").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	res, err := s.usecase.Cancel(ctx, request.GetOrderId(), strings.TrimSpace(request.GetReason()))
+	if err != nil {
+		log.Error().Err(err).Msg("failed Cancel")
+		return nil, err
+	}
+
+	return toOrderProto(res), nil
+}
+
+func (s *service) RejectOrder(ctx context.Context, request *orderpb.RejectOrderRequest) (*orderpb.Order, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.order.RejectOrder").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	reason := strings.TrimSpace(request.GetReason())
+	if reason == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	res, err := s.usecase.Reject(ctx, request.GetOrderId(), reason)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Reject")
+		return nil, err
+	}
+
+	return toOrderProto(res), nil
+}
+
+func (s *service) GetOrderTimeline(ctx context.Context, request *orderpb.GetOrderTimelineRequest) (*orderpb.GetOrderTimelineResponse, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.order.GetOrderTimeline").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	res, err := s.usecase.Timeline(ctx, request.GetOrderId())
+	if err != nil {
+		log.Error().Err(err).Msg("failed Timeline")
+		return nil, err
+	}
+
+	events := make([]*orderpb.OrderStatusEvent, 0, len(res))
+	for _, change := range res {
+		events = append(events, &orderpb.OrderStatusEvent{
+			FromStatus: change.From,
+			ToStatus:   change.To,
+			ActorId:    change.ActorID,
+			ActorType:  string(change.ActorType),
+			Reason:     change.Reason,
+			CreatedAt:  timestamppb.New(change.CreatedAt),
+		})
+	}
+
+	return &orderpb.GetOrderTimelineResponse{Events: events}, nil
+}
+
+func toOrderProto(order models.Order) *orderpb.Order {
+	discounts := []*orderpb.OrderDiscount{}
+	for _, discount := range order.Discounts {
+		discounts = append(discounts, &orderpb.OrderDiscount{
+			CouponId: discount.CouponID,
+			Code:     discount.Code,
+			Amount:   discount.Amount.Proto(),
+		})
+	}
+
+	items := []*orderpb.OrderProduct{}
+	for _, item := range order.Items {
+		items = append(items, &orderpb.OrderProduct{
+			ProductId:  item.ProductID,
+			Quantity:   item.Quantity,
+			Name:       item.Product.Name,
+			UnitPrice:  item.Product.Price.Proto(),
+			SellerId:   item.Product.Seller.ID,
+			SellerName: item.Product.Seller.Name,
+		})
+	}
+
+	return &orderpb.Order{
+		Id:                 order.ID,
+		CheckoutId:         order.CheckoutID,
+		Description:        order.Description,
+		SourceAddress:      order.SourceAddress,
+		DestinationAddress: order.DestinationAddress,
+		Subtotal:           order.Subtotal.Proto(),
+		Discounts:          discounts,
+		TotalPrice:         order.TotalPrice.Proto(),
+		OrderStatus:        order.Status,
+		PaymentStatus:      order.PaymentStatus,
+		Seller: &userpb.User{
+			Name:    order.Seller.Name,
+			Address: order.Seller.Address,
+		},
+		Buyer: &userpb.User{
+			Name:    order.Buyer.Name,
+			Address: order.Buyer.Address,
+		},
+		Items:     items,
+		CreatedAt: order.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt: order.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
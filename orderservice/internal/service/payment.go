@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	"github.com/situmorangbastian/skyros/orderservice/internal/usecase"
+	paymentpb "github.com/situmorangbastian/skyros/proto/payment"
+)
+
+type paymentService struct {
+	usecase usecase.PaymentUsecase
+}
+
+func NewPaymentService(usecase usecase.PaymentUsecase) paymentpb.PaymentServiceServer {
+	return &paymentService{
+		usecase: usecase,
+	}
+}
+
+func (s *paymentService) CreatePaymentIntent(ctx context.Context, request *paymentpb.CreatePaymentIntentRequest) (*paymentpb.PaymentIntent, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.payment.CreatePaymentIntent").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	paymentMethod := strings.TrimSpace(request.GetPaymentMethod())
+	if paymentMethod == "" {
+		return nil, status.Error(codes.InvalidArgument, "payment_method is required")
+	}
+
+	res, err := s.usecase.Create(ctx, request.GetOrderId(), paymentMethod)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Create")
+		return nil, err
+	}
+
+	return toPaymentIntentProto(res), nil
+}
+
+func (s *paymentService) GetPaymentIntents(ctx context.Context, request *paymentpb.GetPaymentIntentsRequest) (*paymentpb.GetPaymentIntentsResponse, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.payment.GetPaymentIntents").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	res, err := s.usecase.Fetch(ctx, request.GetOrderId())
+	if err != nil {
+		log.Error().Err(err).Msg("failed Fetch")
+		return nil, err
+	}
+
+	result := make([]*paymentpb.PaymentIntent, 0, len(res))
+	for _, intent := range res {
+		result = append(result, toPaymentIntentProto(intent))
+	}
+
+	return &paymentpb.GetPaymentIntentsResponse{Result: result}, nil
+}
+
+func (s *paymentService) CapturePayment(ctx context.Context, request *paymentpb.CapturePaymentRequest) (*paymentpb.PaymentIntent, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.payment.CapturePayment").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetPaymentIntentId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "payment_intent_id is required")
+	}
+
+	res, err := s.usecase.Capture(ctx, request.GetPaymentIntentId())
+	if err != nil {
+		log.Error().Err(err).Msg("failed Capture")
+		return nil, err
+	}
+
+	return toPaymentIntentProto(res), nil
+}
+
+func (s *paymentService) RefundPayment(ctx context.Context, request *paymentpb.RefundPaymentRequest) (*paymentpb.PaymentIntent, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.payment.RefundPayment").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetPaymentIntentId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "payment_intent_id is required")
+	}
+
+	res, err := s.usecase.Refund(ctx, request.GetPaymentIntentId())
+	if err != nil {
+		log.Error().Err(err).Msg("failed Refund")
+		return nil, err
+	}
+
+	return toPaymentIntentProto(res), nil
+}
+
+func (s *paymentService) HandlePaymentWebhook(ctx context.Context, request *paymentpb.HandlePaymentWebhookRequest) (*paymentpb.HandlePaymentWebhookResponse, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.payment.HandlePaymentWebhook").Logger()
+	log.Info().Str("provider", request.GetProvider()).Msg("request received")
+
+	err := s.usecase.HandleWebhook(ctx, request.GetProvider(), request.GetPayload(), request.GetSignature())
+	if err != nil {
+		log.Error().Err(err).Msg("failed HandleWebhook")
+		return nil, err
+	}
+
+	return &paymentpb.HandlePaymentWebhookResponse{}, nil
+}
+
+func toPaymentIntentProto(intent models.PaymentIntent) *paymentpb.PaymentIntent {
+	return &paymentpb.PaymentIntent{
+		Id:            intent.ID,
+		OrderId:       intent.OrderID,
+		Provider:      intent.Provider,
+		Amount:        intent.Amount.Proto(),
+		Status:        intent.Status,
+		FailureReason: intent.FailureReason,
+		CreatedAt:     timestamppb.New(intent.CreatedAt),
+		UpdatedAt:     timestamppb.New(intent.UpdatedAt),
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	"github.com/situmorangbastian/skyros/orderservice/internal/usecase"
+	promotionpb "github.com/situmorangbastian/skyros/proto/promotion"
+	"github.com/situmorangbastian/skyros/serviceutils/money"
+)
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,64}$`)
+
+type promotionService struct {
+	usecase usecase.PromotionUsecase
+}
+
+func NewPromotionService(usecase usecase.PromotionUsecase) promotionpb.PromotionServiceServer {
+	return &promotionService{
+		usecase: usecase,
+	}
+}
+
+func (s *promotionService) CreateCoupon(ctx context.Context, request *promotionpb.CreateCouponRequest) (*promotionpb.Coupon, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.promotion.CreateCoupon").Logger()
+	log.Info().Msg("request received")
+
+	coupon := models.Coupon{
+		Code:                   models.NormalizeCouponCode(request.GetCode()),
+		DiscountType:           request.GetDiscountType(),
+		PercentOff:             request.GetPercentOff(),
+		AmountOff:              money.FromProto(request.GetAmountOff()),
+		MinOrderValue:          money.FromProto(request.GetMinOrderValue()),
+		StartsAt:               time.Now().UTC(),
+		MaxRedemptions:         request.GetMaxRedemptions(),
+		MaxRedemptionsPerBuyer: request.GetMaxRedemptionsPerBuyer(),
+	}
+	if request.GetStartsAt() != nil {
+		coupon.StartsAt = request.GetStartsAt().AsTime()
+	}
+	if request.GetEndsAt() != nil {
+		coupon.EndsAt = request.GetEndsAt().AsTime()
+	}
+
+	if err := validateCoupon(coupon); err != nil {
+		return nil, err
+	}
+
+	res, err := s.usecase.StoreCoupon(ctx, coupon)
+	if err != nil {
+		log.Error().Err(err).Msg("failed StoreCoupon")
+		return nil, err
+	}
+
+	return toCouponProto(res), nil
+}
+
+func (s *promotionService) GetCoupons(ctx context.Context, request *promotionpb.GetCouponsRequest) (*promotionpb.GetCouponsResponse, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.promotion.GetCoupons").Logger()
+	log.Info().Msg("request received")
+
+	res, err := s.usecase.FetchCoupons(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed FetchCoupons")
+		return nil, err
+	}
+
+	result := make([]*promotionpb.Coupon, 0, len(res))
+	for _, coupon := range res {
+		result = append(result, toCouponProto(coupon))
+	}
+
+	return &promotionpb.GetCouponsResponse{Result: result}, nil
+}
+
+func validateCoupon(coupon models.Coupon) error {
+	if !couponCodePattern.MatchString(coupon.Code) {
+		return status.Error(codes.InvalidArgument, "code must be 3 to 64 letters, digits, '-' or '_'")
+	}
+
+	switch coupon.DiscountType {
+	case promotionpb.DiscountType_DISCOUNT_TYPE_PERCENTAGE:
+		if coupon.PercentOff < 1 || coupon.PercentOff > 100 {
+			return status.Error(codes.InvalidArgument, "percent_off must be between 1 and 100")
+		}
+		if !coupon.AmountOff.IsZero() {
+			return status.Error(codes.InvalidArgument, "amount_off is not allowed for percentage coupons")
+		}
+	case promotionpb.DiscountType_DISCOUNT_TYPE_FIXED:
+		if coupon.AmountOff.MinorUnits <= 0 || !money.ValidCurrencyCode(coupon.AmountOff.CurrencyCode) {
+			return status.Error(codes.InvalidArgument, "amount_off must be a positive amount with an ISO 4217 currency_code")
+		}
+		if coupon.PercentOff != 0 {
+			return status.Error(codes.InvalidArgument, "percent_off is not allowed for fixed coupons")
+		}
+	default:
+		return status.Error(codes.InvalidArgument, "discount_type is required")
+	}
+
+	if !coupon.MinOrderValue.IsZero() {
+		if coupon.MinOrderValue.MinorUnits <= 0 || !money.ValidCurrencyCode(coupon.MinOrderValue.CurrencyCode) {
+			return status.Error(codes.InvalidArgument, "min_order_value must be a positive amount with an ISO 4217 currency_code")
+		}
+		if !coupon.AmountOff.IsZero() && coupon.AmountOff.CurrencyCode != coupon.MinOrderValue.CurrencyCode {
+			return status.Error(codes.InvalidArgument, "min_order_value and amount_off must have the same currency")
+		}
+	}
+
+	if !coupon.EndsAt.IsZero() && !coupon.EndsAt.After(coupon.StartsAt) {
+		return status.Error(codes.InvalidArgument, "ends_at must be after starts_at")
+	}
+
+	if coupon.MaxRedemptions < 0 || coupon.MaxRedemptionsPerBuyer < 0 {
+		return status.Error(codes.InvalidArgument, "redemption limits must not be negative")
+	}
+
+	return nil
+}
+
+func toCouponProto(coupon models.Coupon) *promotionpb.Coupon {
+	result := &promotionpb.Coupon{
+		Id:                     coupon.ID,
+		Code:                   coupon.Code,
+		SellerId:               coupon.SellerID,
+		DiscountType:           coupon.DiscountType,
+		PercentOff:             coupon.PercentOff,
+		StartsAt:               timestamppb.New(coupon.StartsAt),
+		MaxRedemptions:         coupon.MaxRedemptions,
+		MaxRedemptionsPerBuyer: coupon.MaxRedemptionsPerBuyer,
+		Redemptions:            coupon.Redemptions,
+		CreatedAt:              timestamppb.New(coupon.CreatedAt),
+	}
+	if !coupon.AmountOff.IsZero() {
+		result.AmountOff = coupon.AmountOff.Proto()
+	}
+	if !coupon.MinOrderValue.IsZero() {
+		result.MinOrderValue = coupon.MinOrderValue.Proto()
+	}
+	if !coupon.EndsAt.IsZero() {
+		result.EndsAt = timestamppb.New(coupon.EndsAt)
+	}
+	return result
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	"github.com/situmorangbastian/skyros/orderservice/internal/usecase"
+	shipmentpb "github.com/situmorangbastian/skyros/proto/shipment"
+	"github.com/situmorangbastian/skyros/serviceutils"
+)
+
+type shipmentService struct {
+	usecase   usecase.ShipmentUsecase
+	validator serviceutils.CustomValidator
+}
+
+func NewShipmentService(usecase usecase.ShipmentUsecase, validator serviceutils.CustomValidator) shipmentpb.ShipmentServiceServer {
+	return &shipmentService{
+		usecase:   usecase,
+		validator: validator,
+	}
+}
+
+func (s *shipmentService) CreateShipment(ctx context.Context, request *shipmentpb.CreateShipmentRequest) (*shipmentpb.Shipment, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.shipment.CreateShipment").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	shipment := models.Shipment{
+		OrderID:        request.GetOrderId(),
+		Carrier:        strings.TrimSpace(request.GetCarrier()),
+		TrackingNumber: strings.TrimSpace(request.GetTrackingNumber()),
+		SourceAddress:  strings.TrimSpace(request.GetSourceAddress()),
+		Items:          []models.ShipmentItem{},
+	}
+	for _, item := range request.GetItems() {
+		shipment.Items = append(shipment.Items, models.ShipmentItem{
+			ProductID: item.GetProductId(),
+			Quantity:  item.GetQuantity(),
+		})
+	}
+
+	if err := s.validator.Validate(shipment); err != nil {
+		return nil, err
+	}
+
+	res, err := s.usecase.Create(ctx, shipment)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Create")
+		return nil, err
+	}
+
+	return toShipmentProto(res), nil
+}
+
+func (s *shipmentService) GetShipments(ctx context.Context, request *shipmentpb.GetShipmentsRequest) (*shipmentpb.GetShipmentsResponse, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.shipment.GetShipments").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	res, err := s.usecase.Fetch(ctx, request.GetOrderId())
+	if err != nil {
+		log.Error().Err(err).Msg("failed Fetch")
+		return nil, err
+	}
+
+	result := make([]*shipmentpb.Shipment, 0, len(res))
+	for _, shipment := range res {
+		result = append(result, toShipmentProto(shipment))
+	}
+
+	return &shipmentpb.GetShipmentsResponse{Result: result}, nil
+}
+
+func (s *shipmentService) GetShipment(ctx context.Context, request *shipmentpb.GetShipmentRequest) (*shipmentpb.Shipment, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.shipment.GetShipment").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetShipmentId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "shipment_id is required")
+	}
+
+	res, err := s.usecase.Get(ctx, request.GetShipmentId())
+	if err != nil {
+		log.Error().Err(err).Msg("failed Get")
+		return nil, err
+	}
+
+	return toShipmentProto(res), nil
+}
+
+func (s *shipmentService) AddTrackingEvent(ctx context.Context, request *shipmentpb.AddTrackingEventRequest) (*shipmentpb.Shipment, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.shipment.AddTrackingEvent").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetShipmentId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "shipment_id is required")
+	}
+
+	if _, ok := shipmentpb.ShipmentStatus_name[int32(request.GetStatus())]; !ok || request.GetStatus() == shipmentpb.ShipmentStatus_SHIPMENT_STATUS_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+
+	event := models.TrackingEvent{
+		Status:      request.GetStatus(),
+		Location:    strings.TrimSpace(request.GetLocation()),
+		Description: strings.TrimSpace(request.GetDescription()),
+	}
+	if request.GetOccurredAt() != nil {
+		event.OccurredAt = request.GetOccurredAt().AsTime()
+	}
+
+	if err := s.validator.Validate(event); err != nil {
+		return nil, err
+	}
+
+	res, err := s.usecase.Track(ctx, request.GetShipmentId(), event)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Track")
+		return nil, err
+	}
+
+	return toShipmentProto(res), nil
+}
+
+func toShipmentProto(shipment models.Shipment) *shipmentpb.Shipment {
+	result := &shipmentpb.Shipment{
+		Id:                 shipment.ID,
+		OrderId:            shipment.OrderID,
+		Carrier:            shipment.Carrier,
+		TrackingNumber:     shipment.TrackingNumber,
+		SourceAddress:      shipment.SourceAddress,
+		DestinationAddress: shipment.DestinationAddress,
+		Status:             shipment.Status,
+		Items:              make([]*shipmentpb.ShipmentItem, 0, len(shipment.Items)),
+		Events:             make([]*shipmentpb.TrackingEvent, 0, len(shipment.Events)),
+		CreatedAt:          timestamppb.New(shipment.CreatedAt),
+		UpdatedAt:          timestamppb.New(shipment.UpdatedAt),
+	}
+
+	for _, item := range shipment.Items {
+		result.Items = append(result.Items, &shipmentpb.ShipmentItem{
+			ProductId: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	for _, event := range shipment.Events {
+		result.Events = append(result.Events, &shipmentpb.TrackingEvent{
+			Status:      event.Status,
+			Location:    event.Location,
+			Description: event.Description,
+			OccurredAt:  timestamppb.New(event.OccurredAt),
+			CreatedAt:   timestamppb.New(event.CreatedAt),
+		})
+	}
+
+	return result
+}
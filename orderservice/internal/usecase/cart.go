@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/integration"
+	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	"github.com/situmorangbastian/skyros/orderservice/internal/repository"
+	cartpb "github.com/situmorangbastian/skyros/proto/cart"
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+)
+
+type CartUsecase interface {
+	Get(ctx context.Context) (models.Cart, error)
+	AddItem(ctx context.Context, item models.CartItem) (models.Cart, error)
+	UpdateItem(ctx context.Context, item models.CartItem) (models.Cart, error)
+	RemoveItem(ctx context.Context, productID string) (models.Cart, error)
+	Checkout(ctx context.Context, order models.Order) (models.Checkout, error)
+}
+
+type cartUsecase struct {
+	cartRepo      repository.CartRepository
+	orderUsecase  OrderUsecase
+	productClient integration.ProductClient
+	ttl           time.Duration
+}
+
+// NewCartUsecase returns a CartUsecase whose carts expire ttl after their last change.
+func NewCartUsecase(
+	cartRepo repository.CartRepository,
+	orderUsecase OrderUsecase,
+	productClient integration.ProductClient,
+	ttl time.Duration) CartUsecase {
+	return &cartUsecase{
+		cartRepo:      cartRepo,
+		orderUsecase:  orderUsecase,
+		productClient: productClient,
+		ttl:           ttl,
+	}
+}
+
+// Get returns the calling buyer's cart priced at the products' current prices.
+func (u *cartUsecase) Get(ctx context.Context) (models.Cart, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.cart.Get").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.Cart{}, err
+	}
+
+	cart, err := u.cartRepo.Get(ctx, user.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Get")
+		return models.Cart{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	if err := u.price(ctx, &cart); err != nil {
+		return models.Cart{}, err
+	}
+
+	return cart, nil
+}
+
+func (u *cartUsecase) AddItem(ctx context.Context, item models.CartItem) (models.Cart, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.cart.AddItem").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.Cart{}, err
+	}
+
+	product, err := u.fetchProduct(ctx, item.ProductID)
+	if err != nil {
+		return models.Cart{}, err
+	}
+
+	cart, err := u.cartRepo.Get(ctx, user.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Get")
+		return models.Cart{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	for _, current := range cart.Items {
+		if current.ProductID != item.ProductID && current.AddedPrice.CurrencyCode != product.Price.CurrencyCode {
+			return models.Cart{}, status.Error(codes.FailedPrecondition, "a cart cannot mix currencies")
+		}
+	}
+
+	item.AddedPrice = product.Price
+	err = u.cartRepo.AddItem(ctx, user.ID, item, time.Now().UTC().Add(u.ttl))
+	if err != nil {
+		log.Error().Err(err).Msg("failed AddItem")
+		return models.Cart{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return u.Get(ctx)
+}
+
+// UpdateItem sets the quantity of an item and accepts the product's current price.
+func (u *cartUsecase) UpdateItem(ctx context.Context, item models.CartItem) (models.Cart, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.cart.UpdateItem").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.Cart{}, err
+	}
+
+	product, err := u.fetchProduct(ctx, item.ProductID)
+	if err != nil {
+		return models.Cart{}, err
+	}
+
+	item.AddedPrice = product.Price
+	err = u.cartRepo.UpdateItem(ctx, user.ID, item, time.Now().UTC().Add(u.ttl))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return models.Cart{}, status.Error(codes.NotFound, "product is not in the cart")
+		}
+		log.Error().Err(err).Msg("failed UpdateItem")
+		return models.Cart{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return u.Get(ctx)
+}
+
+func (u *cartUsecase) RemoveItem(ctx context.Context, productID string) (models.Cart, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.cart.RemoveItem").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.Cart{}, err
+	}
+
+	err = u.cartRepo.RemoveItem(ctx, user.ID, productID, time.Now().UTC().Add(u.ttl))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return models.Cart{}, status.Error(codes.NotFound, "product is not in the cart")
+		}
+		log.Error().Err(err).Msg("failed RemoveItem")
+		return models.Cart{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return u.Get(ctx)
+}
+
+// Checkout places the cart through OrderUsecase.Store and empties it. Carts with
+// warnings are refused so the buyer reviews changed prices and missing products first.
+func (u *cartUsecase) Checkout(ctx context.Context, order models.Order) (models.Checkout, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.cart.Checkout").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.Checkout{}, err
+	}
+
+	cart, err := u.Get(ctx)
+	if err != nil {
+		return models.Checkout{}, err
+	}
+
+	if len(cart.Items) == 0 {
+		return models.Checkout{}, status.Error(codes.FailedPrecondition, "cart is empty")
+	}
+
+	order.Items = []models.OrderProduct{}
+	for _, item := range cart.Items {
+		if len(item.Warnings) > 0 {
+			return models.Checkout{}, status.Error(codes.FailedPrecondition, "cart has items with changed prices or unavailable products")
+		}
+		order.Items = append(order.Items, models.OrderProduct{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	result, err := u.orderUsecase.Store(ctx, order)
+	if err != nil {
+		return models.Checkout{}, err
+	}
+
+	// The orders are placed; a cart left behind is only an inconvenience.
+	if err := u.cartRepo.Delete(ctx, user.ID); err != nil {
+		log.Error().Err(err).Str("checkout_id", result.ID).Msg("failed Delete")
+	}
+
+	return result, nil
+}
+
+func (u *cartUsecase) fetchProduct(ctx context.Context, productID string) (models.Product, error) {
+	log := zerolog.Ctx(ctx)
+
+	products, err := u.productClient.FetchByIDs(ctx, []string{productID})
+	if err != nil {
+		log.Error().Err(err).Msg("failed FetchByIDs")
+		return models.Product{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	product, ok := products[productID]
+	if !ok {
+		return models.Product{}, status.Error(codes.NotFound, "product not found")
+	}
+
+	return product, nil
+}
+
+// price fills in the current product, subtotal and warnings of every item and the
+// cart total. When productservice cannot be reached every item is reported as
+// unavailable rather than failing the read.
+func (u *cartUsecase) price(ctx context.Context, cart *models.Cart) error {
+	log := zerolog.Ctx(ctx)
+
+	if len(cart.Items) == 0 {
+		return nil
+	}
+
+	productIds := []string{}
+	for _, item := range cart.Items {
+		productIds = append(productIds, item.ProductID)
+	}
+
+	products, err := u.productClient.FetchByIDs(ctx, productIds)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed FetchByIDs, pricing cart as unavailable")
+	}
+
+	for index := range cart.Items {
+		item := &cart.Items[index]
+
+		product, ok := products[item.ProductID]
+		if !ok {
+			item.Warnings = append(item.Warnings, cartpb.CartItemWarning_CART_ITEM_WARNING_PRODUCT_UNAVAILABLE)
+			continue
+		}
+
+		item.Product = product
+		if product.Price != item.AddedPrice {
+			item.Warnings = append(item.Warnings, cartpb.CartItemWarning_CART_ITEM_WARNING_PRICE_CHANGED)
+		}
+
+		item.Subtotal, err = product.Price.Multiply(item.Quantity)
+		if err != nil {
+			return totalError(err)
+		}
+
+		cart.Total, err = cart.Total.Add(item.Subtotal)
+		if err != nil {
+			return totalError(err)
+		}
+	}
+
+	return nil
+}
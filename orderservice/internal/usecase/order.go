@@ -3,6 +3,8 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/codes"
@@ -11,51 +13,68 @@ import (
 	"github.com/situmorangbastian/skyros/orderservice/internal/integration"
 	"github.com/situmorangbastian/skyros/orderservice/internal/models"
 	"github.com/situmorangbastian/skyros/orderservice/internal/repository"
+	orderpb "github.com/situmorangbastian/skyros/proto/order"
+	paymentpb "github.com/situmorangbastian/skyros/proto/payment"
 	"github.com/situmorangbastian/skyros/serviceutils/auth"
+	"github.com/situmorangbastian/skyros/serviceutils/money"
 )
 
 type OrderUsecase interface {
-	Store(ctx context.Context, order models.Order) (models.Order, error)
+	Store(ctx context.Context, order models.Order) (models.Checkout, error)
 	Get(ctx context.Context, ID string) (models.Order, error)
 	Fetch(ctx context.Context, filter models.Filter) ([]models.Order, error)
-	PatchStatus(ctx context.Context, ID string, status int) error
+	PatchStatus(ctx context.Context, ID string, status orderpb.OrderStatus) (models.Order, error)
+	Cancel(ctx context.Context, ID string, reason string) (models.Order, error)
+	Reject(ctx context.Context, ID string, reason string) (models.Order, error)
+	Timeline(ctx context.Context, ID string) ([]models.StatusChange, error)
 }
 
 type usecase struct {
 	orderRepo     repository.OrderRepository
+	promotionRepo repository.PromotionRepository
 	userClient    auth.UserClient
+	addressClient integration.AddressClient
 	productClient integration.ProductClient
 	logger        zerolog.Logger
 }
 
 func NewUsecase(
 	orderRepo repository.OrderRepository,
+	promotionRepo repository.PromotionRepository,
 	userClient auth.UserClient,
+	addressClient integration.AddressClient,
 	productClient integration.ProductClient,
 	logger zerolog.Logger) OrderUsecase {
 	return &usecase{
 		orderRepo:     orderRepo,
+		promotionRepo: promotionRepo,
 		userClient:    userClient,
+		addressClient: addressClient,
 		productClient: productClient,
 		logger:        logger,
 	}
 }
 
-func (u *usecase) Store(ctx context.Context, order models.Order) (models.Order, error) {
+// Store places the cart described by order. Items are grouped by seller and every
+// group becomes its own order, all of them saved under a single checkout.
+func (u *usecase) Store(ctx context.Context, order models.Order) (models.Checkout, error) {
 	log := zerolog.Ctx(ctx)
 	log.With().Str("func", "internal.usecase.user.Store").Logger()
 
 	user, err := auth.GetUserClaims(ctx)
 	if err != nil {
-		return models.Order{}, err
+		return models.Checkout{}, err
 	}
 
-	if user.Type != auth.UserBuyerType {
-		return models.Order{}, status.Error(codes.NotFound, "Not Found")
+	order.DestinationAddress, err = u.addressClient.GetAddress(ctx, user.ID, order.AddressID)
+	if err != nil {
+		if errors.Is(err, integration.ErrAddressNotFound) {
+			return models.Checkout{}, status.Error(codes.NotFound, "address not found")
+		}
+		log.Error().Err(err).Msg("failed GetAddress")
+		return models.Checkout{}, status.Error(codes.Internal, "Internal Server Error")
 	}
 
-	order.Buyer.ID = user.ID
-	order.TotalPrice = 0
 	productIds := []string{}
 	for _, item := range order.Items {
 		productIds = append(productIds, item.ProductID)
@@ -64,27 +83,161 @@ func (u *usecase) Store(ctx context.Context, order models.Order) (models.Order,
 	products, err := u.productClient.FetchByIDs(ctx, productIds)
 	if err != nil {
 		log.Error().Err(err).Msg("failed FetchByIDs")
-		return models.Order{}, status.Error(codes.Internal, "Internal Server Error")
+		return models.Checkout{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	checkout := models.Checkout{
+		Buyer:  *user,
+		Orders: []models.Order{},
+	}
+	sellerOrder := map[string]int{}
+	for _, item := range order.Items {
+		item.Product = products[item.ProductID]
+		if item.Product.Name == "" {
+			return models.Checkout{}, status.Error(codes.NotFound, "product not found")
+		}
+
+		index, ok := sellerOrder[item.Product.Seller.ID]
+		if !ok {
+			index = len(checkout.Orders)
+			sellerOrder[item.Product.Seller.ID] = index
+			checkout.Orders = append(checkout.Orders, models.Order{
+				Buyer:              *user,
+				Seller:             item.Product.Seller,
+				Description:        order.Description,
+				SourceAddress:      item.Product.Seller.Address,
+				DestinationAddress: order.DestinationAddress,
+				Status:             orderpb.OrderStatus_ORDER_STATUS_PENDING,
+				Items:              []models.OrderProduct{},
+			})
+		}
+
+		subtotal, err := item.Product.Price.Multiply(item.Quantity)
+		if err != nil {
+			return models.Checkout{}, totalError(err)
+		}
+
+		checkout.Orders[index].Items = append(checkout.Orders[index].Items, item)
+		checkout.Orders[index].Subtotal, err = checkout.Orders[index].Subtotal.Add(subtotal)
+		if err != nil {
+			return models.Checkout{}, totalError(err)
+		}
+	}
+
+	if order.CouponCode != "" {
+		if err := u.applyCoupon(ctx, &checkout, order.CouponCode); err != nil {
+			return models.Checkout{}, err
+		}
+	}
+
+	for index := range checkout.Orders {
+		total := checkout.Orders[index].Subtotal
+		for _, discount := range checkout.Orders[index].Discounts {
+			total, err = total.Sub(discount.Amount)
+			if err != nil {
+				return models.Checkout{}, totalError(err)
+			}
+		}
+		checkout.Orders[index].TotalPrice = total
+
+		checkout.TotalPrice, err = checkout.TotalPrice.Add(total)
+		if err != nil {
+			return models.Checkout{}, totalError(err)
+		}
 	}
 
-	for index := range order.Items {
-		order.Items[index].Product = products[order.Items[index].ProductID]
-		if order.Items[index].Product.Name == "" {
-			return models.Order{}, status.Error(codes.NotFound, "product not found")
+	for index := range checkout.Orders {
+		reservationID, err := u.productClient.ReserveStock(ctx, checkout.Orders[index].Items)
+		if err != nil {
+			u.releaseCheckoutStock(ctx, checkout)
+			if st, ok := status.FromError(err); ok && st.Code() == codes.FailedPrecondition {
+				return models.Checkout{}, status.Error(codes.FailedPrecondition, st.Message())
+			}
+			log.Error().Err(err).Msg("failed ReserveStock")
+			return models.Checkout{}, status.Error(codes.Internal, "Internal Server Error")
 		}
-		order.Seller = order.Items[index].Product.Seller
-		order.TotalPrice += int64(order.Items[index].Product.Price) * order.Items[index].Quantity
+		checkout.Orders[index].StockReservationID = reservationID
 	}
 
-	result, err := u.orderRepo.Store(ctx, order)
+	result, err := u.orderRepo.StoreCheckout(ctx, checkout)
 	if err != nil {
-		log.Error().Err(err).Msg("failed Store")
-		return models.Order{}, status.Error(codes.Internal, "Internal Server Error")
+		u.releaseCheckoutStock(ctx, checkout)
+		if err == repository.ErrCouponLimitReached {
+			return models.Checkout{}, status.Error(codes.FailedPrecondition, "coupon usage limit reached")
+		}
+		log.Error().Err(err).Msg("failed StoreCheckout")
+		return models.Checkout{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	for _, order := range result.Orders {
+		if err := u.productClient.CommitStock(ctx, order.StockReservationID); err != nil {
+			log.Error().Err(err).Str("order_id", order.ID).Str("reservation_id", order.StockReservationID).Msg("failed CommitStock")
+		}
 	}
 
 	return result, nil
 }
 
+// applyCoupon adds the coupon's discount to the checkout order of the seller who
+// issued it. Usage limits are enforced when the checkout is stored.
+func (u *usecase) applyCoupon(ctx context.Context, checkout *models.Checkout, code string) error {
+	log := zerolog.Ctx(ctx)
+
+	coupon, err := u.promotionRepo.GetCouponByCode(ctx, models.NormalizeCouponCode(code))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return status.Error(codes.NotFound, "coupon not found")
+		}
+		log.Error().Err(err).Msg("failed GetCouponByCode")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	if !coupon.ActiveAt(time.Now().UTC()) {
+		return status.Error(codes.FailedPrecondition, "coupon is not active")
+	}
+
+	for index := range checkout.Orders {
+		order := &checkout.Orders[index]
+		if order.Seller.ID != coupon.SellerID {
+			continue
+		}
+
+		if !coupon.MinOrderValue.IsZero() {
+			if coupon.MinOrderValue.CurrencyCode != order.Subtotal.CurrencyCode {
+				return status.Error(codes.FailedPrecondition, "coupon does not apply to this order")
+			}
+			if order.Subtotal.MinorUnits < coupon.MinOrderValue.MinorUnits {
+				return status.Error(codes.FailedPrecondition, "order subtotal is below the coupon minimum")
+			}
+		}
+
+		amount, err := coupon.Discount(order.Subtotal)
+		if err != nil {
+			if errors.Is(err, money.ErrCurrencyMismatch) {
+				return status.Error(codes.FailedPrecondition, "coupon does not apply to this order")
+			}
+			return totalError(err)
+		}
+
+		order.Discounts = append(order.Discounts, models.OrderDiscount{
+			CouponID: coupon.ID,
+			Code:     coupon.Code,
+			Amount:   amount,
+		})
+		return nil
+	}
+
+	return status.Error(codes.FailedPrecondition, "coupon does not apply to this order")
+}
+
+// totalError reports why the prices of an order could not be added up.
+func totalError(err error) error {
+	if errors.Is(err, money.ErrCurrencyMismatch) {
+		return status.Error(codes.InvalidArgument, "an order cannot mix currencies")
+	}
+	return status.Error(codes.InvalidArgument, "order total is too large")
+}
+
 func (u *usecase) Get(ctx context.Context, ID string) (models.Order, error) {
 	log := zerolog.Ctx(ctx)
 	log.With().Str("func", "internal.usecase.user.Get").Logger()
@@ -104,8 +257,9 @@ func (u *usecase) Get(ctx context.Context, ID string) (models.Order, error) {
 		filter.BuyerID = user.ID
 	case auth.UserSellerType:
 		filter.SellerID = user.ID
+	case auth.UserAdminType:
 	default:
-		return models.Order{}, status.Error(codes.NotFound, "Not Found")
+		return models.Order{}, auth.ErrPermissionDenied
 	}
 
 	result, err := u.orderRepo.Fetch(ctx, filter)
@@ -127,25 +281,7 @@ func (u *usecase) Get(ctx context.Context, ID string) (models.Order, error) {
 	result[0].Buyer = users[result[0].Buyer.ID]
 	result[0].Seller = users[result[0].Seller.ID]
 
-	productIds := []string{}
-	for _, order := range result {
-		for _, item := range order.Items {
-			productIds = append(productIds, item.ProductID)
-		}
-	}
-
-	products, err := u.productClient.FetchByIDs(ctx, productIds)
-	if err != nil {
-		log.Error().Err(err).Msg("failed FetchByIDs")
-		return models.Order{}, status.Error(codes.Internal, "Internal Server Error")
-	}
-
-	for index, order := range result {
-		for index := range order.Items {
-			order.Items[index].Product = products[order.Items[index].ProductID]
-		}
-		result[index].Items = order.Items
-	}
+	u.fillMissingSnapshots(ctx, result)
 
 	return result[0], nil
 }
@@ -164,8 +300,9 @@ func (u *usecase) Fetch(ctx context.Context, filter models.Filter) ([]models.Ord
 		filter.BuyerID = user.ID
 	case auth.UserSellerType:
 		filter.SellerID = user.ID
+	case auth.UserAdminType:
 	default:
-		return []models.Order{}, status.Error(codes.NotFound, "Not Found")
+		return []models.Order{}, auth.ErrPermissionDenied
 	}
 
 	result, err := u.orderRepo.Fetch(ctx, filter)
@@ -175,12 +312,8 @@ func (u *usecase) Fetch(ctx context.Context, filter models.Filter) ([]models.Ord
 	}
 
 	userIds := []string{}
-	productIds := []string{}
 	for _, order := range result {
 		userIds = append(userIds, order.Buyer.ID, order.Seller.ID)
-		for _, item := range order.Items {
-			productIds = append(productIds, item.ProductID)
-		}
 	}
 
 	users, err := u.userClient.FetchByIDs(ctx, userIds)
@@ -189,42 +322,273 @@ func (u *usecase) Fetch(ctx context.Context, filter models.Filter) ([]models.Ord
 		return []models.Order{}, status.Error(codes.Internal, "Internal Server Error")
 	}
 
-	products, err := u.productClient.FetchByIDs(ctx, productIds)
-	if err != nil {
-		log.Error().Err(err).Msg("failed FetchByIDs")
-		return []models.Order{}, status.Error(codes.Internal, "Internal Server Error")
-	}
-
-	for index, order := range result {
+	for index := range result {
 		result[index].Seller = users[result[index].Seller.ID]
 		result[index].Buyer = users[result[index].Buyer.ID]
-		for index := range order.Items {
-			order.Items[index].Product = products[order.Items[index].ProductID]
-		}
-		result[index].Items = order.Items
 	}
 
+	u.fillMissingSnapshots(ctx, result)
+
 	return result, nil
 }
 
-func (u *usecase) PatchStatus(ctx context.Context, ID string, statusOrder int) error {
+func (u *usecase) PatchStatus(ctx context.Context, ID string, statusOrder orderpb.OrderStatus) (models.Order, error) {
 	log := zerolog.Ctx(ctx)
 	log.With().Str("func", "internal.usecase.user.PatchStatus").Logger()
 
+	switch statusOrder {
+	case orderpb.OrderStatus_ORDER_STATUS_CANCELLED, orderpb.OrderStatus_ORDER_STATUS_REJECTED:
+		return models.Order{}, status.Error(codes.InvalidArgument, "use CancelOrder or RejectOrder to cancel or reject an order")
+	case orderpb.OrderStatus_ORDER_STATUS_PAID:
+		return models.Order{}, status.Error(codes.InvalidArgument, "orders are marked paid when their payment succeeds")
+	}
+
 	user, err := auth.GetUserClaims(ctx)
 	if err != nil {
-		return err
+		return models.Order{}, err
 	}
 
-	if user.Type != auth.UserSellerType {
-		return status.Error(codes.NotFound, "Not Found")
+	filter := models.Filter{
+		OrderID:  ID,
+		PageSize: 1,
+	}
+	if user.Type != auth.UserAdminType {
+		filter.SellerID = user.ID
+	}
+
+	order, err := fetchOrder(ctx, u.orderRepo, filter)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	if !models.CanTransitionStatus(order.Status, statusOrder) {
+		return models.Order{}, status.Errorf(codes.FailedPrecondition, "cannot change order status from %s to %s", order.Status, statusOrder)
 	}
 
-	err = u.orderRepo.PatchStatus(ctx, ID, statusOrder)
+	return u.changeStatus(ctx, *user, order, statusOrder, "")
+}
+
+// Cancel cancels an order on behalf of its buyer. Orders can only be cancelled before
+// they are shipped.
+func (u *usecase) Cancel(ctx context.Context, ID string, reason string) (models.Order, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.user.Cancel").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
 	if err != nil {
+		return models.Order{}, err
+	}
+
+	filter := models.Filter{
+		OrderID:  ID,
+		PageSize: 1,
+	}
+	if user.Type != auth.UserAdminType {
+		filter.BuyerID = user.ID
+	}
+
+	order, err := fetchOrder(ctx, u.orderRepo, filter)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	switch order.Status {
+	case orderpb.OrderStatus_ORDER_STATUS_PENDING, orderpb.OrderStatus_ORDER_STATUS_PAID, orderpb.OrderStatus_ORDER_STATUS_ACCEPTED:
+	default:
+		return models.Order{}, status.Errorf(codes.FailedPrecondition, "cannot cancel an order in status %s", order.Status)
+	}
+
+	return u.changeStatus(ctx, *user, order, orderpb.OrderStatus_ORDER_STATUS_CANCELLED, reason)
+}
+
+// Reject turns down a pending or paid order on behalf of its seller.
+func (u *usecase) Reject(ctx context.Context, ID string, reason string) (models.Order, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.user.Reject").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	filter := models.Filter{
+		OrderID:  ID,
+		PageSize: 1,
+	}
+	if user.Type != auth.UserAdminType {
+		filter.SellerID = user.ID
+	}
+
+	order, err := fetchOrder(ctx, u.orderRepo, filter)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	switch order.Status {
+	case orderpb.OrderStatus_ORDER_STATUS_PENDING, orderpb.OrderStatus_ORDER_STATUS_PAID:
+	default:
+		return models.Order{}, status.Errorf(codes.FailedPrecondition, "cannot reject an order in status %s", order.Status)
+	}
+
+	return u.changeStatus(ctx, *user, order, orderpb.OrderStatus_ORDER_STATUS_REJECTED, reason)
+}
+
+// Timeline returns the status history of an order visible to the caller.
+func (u *usecase) Timeline(ctx context.Context, ID string) ([]models.StatusChange, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.user.Timeline").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := orderFilter(*user, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fetchOrder(ctx, u.orderRepo, filter); err != nil {
+		return nil, err
+	}
+
+	result, err := u.orderRepo.FetchStatusHistory(ctx, ID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed FetchStatusHistory")
+		return nil, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return result, nil
+}
+
+// orderFilter matches the order orderID when it is visible to user.
+func orderFilter(user auth.Claims, orderID string) (models.Filter, error) {
+	filter := models.Filter{
+		OrderID:  orderID,
+		PageSize: 1,
+	}
+
+	switch user.Type {
+	case auth.UserBuyerType:
+		filter.BuyerID = user.ID
+	case auth.UserSellerType:
+		filter.SellerID = user.ID
+	case auth.UserAdminType:
+	default:
+		return models.Filter{}, auth.ErrPermissionDenied
+	}
+
+	return filter, nil
+}
+
+// fetchOrder loads the single order matched by filter, reporting NotFound when there is none.
+func fetchOrder(ctx context.Context, orderRepo repository.OrderRepository, filter models.Filter) (models.Order, error) {
+	log := zerolog.Ctx(ctx)
+
+	result, err := orderRepo.Fetch(ctx, filter)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Fetch")
+		return models.Order{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	if len(result) == 0 {
+		return models.Order{}, status.Error(codes.NotFound, "Not Found")
+	}
+
+	return result[0], nil
+}
+
+// changeStatus moves order to status on behalf of actor. Orders that will not be
+// fulfilled have their reserved stock released and, once paid, are owed a refund of
+// their total.
+func (u *usecase) changeStatus(ctx context.Context, actor auth.Claims, order models.Order, statusOrder orderpb.OrderStatus, reason string) (models.Order, error) {
+	log := zerolog.Ctx(ctx)
+
+	change := models.StatusChange{
+		OrderID:   order.ID,
+		From:      order.Status,
+		To:        statusOrder,
+		ActorID:   actor.ID,
+		ActorType: actor.Type,
+		Reason:    reason,
+	}
+
+	unfulfilled := statusOrder == orderpb.OrderStatus_ORDER_STATUS_CANCELLED || statusOrder == orderpb.OrderStatus_ORDER_STATUS_REJECTED
+	if unfulfilled && order.PaymentStatus == paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCEEDED {
+		change.Refund = &models.Refund{
+			OrderID: order.ID,
+			Amount:  order.TotalPrice,
+			Reason:  reason,
+			Status:  models.RefundPending,
+		}
+	}
+
+	err := u.orderRepo.PatchStatus(ctx, change)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return models.Order{}, status.Error(codes.FailedPrecondition, "order status has been changed")
+		}
 		log.Error().Err(err).Msg("failed PatchStatus")
-		return status.Error(codes.Internal, "Internal Server Error")
+		return models.Order{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	if unfulfilled {
+		u.releaseStock(ctx, order)
 	}
 
-	return nil
+	return u.Get(ctx, order.ID)
+}
+
+// fillMissingSnapshots completes items placed before product snapshots were stored
+// with the product's current details. Items keep their empty snapshot when
+// productservice is unavailable or the product no longer exists.
+func (u *usecase) fillMissingSnapshots(ctx context.Context, orders []models.Order) {
+	log := zerolog.Ctx(ctx)
+
+	productIds := []string{}
+	for _, order := range orders {
+		for _, item := range order.Items {
+			if item.Product.Name == "" {
+				productIds = append(productIds, item.ProductID)
+			}
+		}
+	}
+
+	if len(productIds) == 0 {
+		return
+	}
+
+	products, err := u.productClient.FetchByIDs(ctx, productIds)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed FetchByIDs, returning items without product snapshot")
+		return
+	}
+
+	for _, order := range orders {
+		for index, item := range order.Items {
+			if product, ok := products[item.ProductID]; ok && item.Product.Name == "" {
+				order.Items[index].Product = product
+			}
+		}
+	}
+}
+
+// releaseStock returns the stock reserved for an order that will not be fulfilled.
+// The status change has already been persisted, so a failure is only logged.
+func (u *usecase) releaseStock(ctx context.Context, order models.Order) {
+	log := zerolog.Ctx(ctx)
+
+	if order.StockReservationID == "" {
+		return
+	}
+
+	if err := u.productClient.ReleaseStock(ctx, order.StockReservationID); err != nil {
+		log.Error().Err(err).Str("order_id", order.ID).Str("reservation_id", order.StockReservationID).Msg("failed ReleaseStock")
+	}
+}
+
+func (u *usecase) releaseCheckoutStock(ctx context.Context, checkout models.Checkout) {
+	for _, order := range checkout.Orders {
+		u.releaseStock(ctx, order)
+	}
 }
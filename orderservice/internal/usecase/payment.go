@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/integration"
+	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	"github.com/situmorangbastian/skyros/orderservice/internal/repository"
+	orderpb "github.com/situmorangbastian/skyros/proto/order"
+	paymentpb "github.com/situmorangbastian/skyros/proto/payment"
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+)
+
+var errProviderUnavailable = status.Error(codes.Unavailable, "payment provider unavailable")
+
+type PaymentUsecase interface {
+	Create(ctx context.Context, orderID string, paymentMethod string) (models.PaymentIntent, error)
+	Fetch(ctx context.Context, orderID string) ([]models.PaymentIntent, error)
+	Capture(ctx context.Context, ID string) (models.PaymentIntent, error)
+	Refund(ctx context.Context, ID string) (models.PaymentIntent, error)
+	HandleWebhook(ctx context.Context, provider string, payload []byte, signature string) error
+}
+
+type paymentUsecase struct {
+	paymentRepo repository.PaymentRepository
+	orderRepo   repository.OrderRepository
+	provider    integration.PaymentProvider
+}
+
+// NewPaymentUsecase returns a PaymentUsecase that takes new payments through provider.
+func NewPaymentUsecase(paymentRepo repository.PaymentRepository, orderRepo repository.OrderRepository, provider integration.PaymentProvider) PaymentUsecase {
+	return &paymentUsecase{
+		paymentRepo: paymentRepo,
+		orderRepo:   orderRepo,
+		provider:    provider,
+	}
+}
+
+// Create authorizes the total of one of the buyer's pending orders.
+func (u *paymentUsecase) Create(ctx context.Context, orderID string, paymentMethod string) (models.PaymentIntent, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.payment.Create").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	order, err := fetchOrder(ctx, u.orderRepo, models.Filter{
+		OrderID:  orderID,
+		BuyerID:  user.ID,
+		PageSize: 1,
+	})
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	if order.Status != orderpb.OrderStatus_ORDER_STATUS_PENDING {
+		return models.PaymentIntent{}, status.Errorf(codes.FailedPrecondition, "cannot pay for an order in status %s", order.Status)
+	}
+
+	intent, err := u.paymentRepo.StoreIntent(ctx, models.PaymentIntent{
+		OrderID:  order.ID,
+		BuyerID:  user.ID,
+		Provider: u.provider.Name(),
+		Amount:   order.TotalPrice,
+		Status:   paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING,
+	})
+	if err != nil {
+		if err == repository.ErrPaymentInProgress {
+			return models.PaymentIntent{}, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		log.Error().Err(err).Msg("failed StoreIntent")
+		return models.PaymentIntent{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	// When the provider cannot be reached the buyer may have been charged anyway, so
+	// the intent stays processing, blocking a second payment, until a webhook settles it.
+	result, err := u.provider.Authorize(ctx, intent.ID, intent.Amount, paymentMethod)
+	if err != nil {
+		log.Error().Err(err).Str("payment_intent_id", intent.ID).Msg("failed Authorize")
+		return models.PaymentIntent{}, errProviderUnavailable
+	}
+
+	return u.apply(ctx, intent, result, "")
+}
+
+// Fetch returns the payment intents of an order visible to the caller.
+func (u *paymentUsecase) Fetch(ctx context.Context, orderID string) ([]models.PaymentIntent, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.payment.Fetch").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := orderFilter(*user, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fetchOrder(ctx, u.orderRepo, filter); err != nil {
+		return nil, err
+	}
+
+	result, err := u.paymentRepo.FetchIntents(ctx, orderID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed FetchIntents")
+		return nil, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return result, nil
+}
+
+// Capture collects an authorized payment of a pending order.
+func (u *paymentUsecase) Capture(ctx context.Context, ID string) (models.PaymentIntent, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.payment.Capture").Logger()
+
+	intent, order, err := u.fetchIntent(ctx, ID)
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	if intent.Status != paymentpb.PaymentStatus_PAYMENT_STATUS_REQUIRES_CAPTURE {
+		return models.PaymentIntent{}, status.Errorf(codes.FailedPrecondition, "cannot capture a payment in status %s", intent.Status)
+	}
+
+	if order.Status != orderpb.OrderStatus_ORDER_STATUS_PENDING {
+		return models.PaymentIntent{}, status.Errorf(codes.FailedPrecondition, "cannot capture a payment of an order in status %s", order.Status)
+	}
+
+	result, err := u.provider.Capture(ctx, intent.ProviderReference, intent.Amount)
+	if err != nil {
+		log.Error().Err(err).Str("payment_intent_id", intent.ID).Msg("failed Capture")
+		return models.PaymentIntent{}, errProviderUnavailable
+	}
+
+	return u.apply(ctx, intent, result, "")
+}
+
+// Refund returns a succeeded payment of a cancelled or rejected order to the buyer.
+func (u *paymentUsecase) Refund(ctx context.Context, ID string) (models.PaymentIntent, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.payment.Refund").Logger()
+
+	intent, order, err := u.fetchIntent(ctx, ID)
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	if intent.Status != paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCEEDED {
+		return models.PaymentIntent{}, status.Errorf(codes.FailedPrecondition, "cannot refund a payment in status %s", intent.Status)
+	}
+
+	switch order.Status {
+	case orderpb.OrderStatus_ORDER_STATUS_CANCELLED, orderpb.OrderStatus_ORDER_STATUS_REJECTED:
+	default:
+		return models.PaymentIntent{}, status.Errorf(codes.FailedPrecondition, "cannot refund a payment of an order in status %s", order.Status)
+	}
+
+	result, err := u.provider.Refund(ctx, intent.ProviderReference, intent.Amount)
+	if err != nil {
+		log.Error().Err(err).Str("payment_intent_id", intent.ID).Msg("failed Refund")
+		return models.PaymentIntent{}, errProviderUnavailable
+	}
+
+	return u.apply(ctx, intent, result, "")
+}
+
+// HandleWebhook applies a provider callback. Callbacks about unknown payments, stale
+// callbacks and redeliveries are acknowledged without effect so the provider stops
+// sending them.
+func (u *paymentUsecase) HandleWebhook(ctx context.Context, provider string, payload []byte, signature string) error {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.payment.HandleWebhook").Logger()
+
+	if provider != u.provider.Name() {
+		return status.Error(codes.NotFound, "unknown payment provider")
+	}
+
+	event, err := u.provider.ParseWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, integration.ErrInvalidSignature) {
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	intent, err := u.webhookIntent(ctx, event)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			log.Warn().Str("event_id", event.ID).Msg("webhook for unknown payment")
+			return nil
+		}
+		log.Error().Err(err).Msg("failed webhookIntent")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	if !models.CanTransitionPayment(intent.Status, event.Status) {
+		log.Info().Str("event_id", event.ID).Str("payment_intent_id", intent.ID).Msg("ignoring stale webhook")
+		return nil
+	}
+
+	_, err = u.apply(ctx, intent, event.PaymentResult, event.ID)
+	return err
+}
+
+// webhookIntent finds the intent a webhook is about by its provider reference, falling
+// back to the intent ID the provider echoes for intents whose authorization never
+// returned a reference.
+func (u *paymentUsecase) webhookIntent(ctx context.Context, event integration.PaymentEvent) (models.PaymentIntent, error) {
+	intent, err := u.paymentRepo.GetIntentByReference(ctx, u.provider.Name(), event.Reference)
+	if err != repository.ErrNotFound || event.IntentID == "" {
+		return intent, err
+	}
+
+	intent, err = u.paymentRepo.GetIntent(ctx, event.IntentID)
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+
+	if intent.Provider != u.provider.Name() || intent.ProviderReference != "" {
+		return models.PaymentIntent{}, repository.ErrNotFound
+	}
+
+	return intent, nil
+}
+
+// apply records a provider result on intent, carrying it over to the order.
+func (u *paymentUsecase) apply(ctx context.Context, intent models.PaymentIntent, result integration.PaymentResult, eventID string) (models.PaymentIntent, error) {
+	log := zerolog.Ctx(ctx)
+
+	if result.Status != intent.Status && !models.CanTransitionPayment(intent.Status, result.Status) {
+		log.Error().Str("payment_intent_id", intent.ID).Msgf("provider moved payment from %s to %s", intent.Status, result.Status)
+		return models.PaymentIntent{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	updated, err := u.paymentRepo.UpdateIntent(ctx, models.PaymentUpdate{
+		IntentID:          intent.ID,
+		From:              intent.Status,
+		To:                result.Status,
+		ProviderReference: result.Reference,
+		FailureReason:     result.FailureReason,
+		EventID:           eventID,
+	})
+	if err != nil {
+		switch err {
+		case repository.ErrPaymentEventApplied:
+			return intent, nil
+		case repository.ErrNotFound:
+			return models.PaymentIntent{}, status.Error(codes.FailedPrecondition, "payment status has been changed")
+		}
+		log.Error().Err(err).Msg("failed UpdateIntent")
+		return models.PaymentIntent{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return updated, nil
+}
+
+// fetchIntent loads a payment intent and its order, scoped to the caller. Intents of
+// another provider cannot be operated on.
+func (u *paymentUsecase) fetchIntent(ctx context.Context, ID string) (models.PaymentIntent, models.Order, error) {
+	log := zerolog.Ctx(ctx)
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.PaymentIntent{}, models.Order{}, err
+	}
+
+	intent, err := u.paymentRepo.GetIntent(ctx, ID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return models.PaymentIntent{}, models.Order{}, status.Error(codes.NotFound, "Not Found")
+		}
+		log.Error().Err(err).Msg("failed GetIntent")
+		return models.PaymentIntent{}, models.Order{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	filter, err := orderFilter(*user, intent.OrderID)
+	if err != nil {
+		return models.PaymentIntent{}, models.Order{}, err
+	}
+
+	order, err := fetchOrder(ctx, u.orderRepo, filter)
+	if err != nil {
+		return models.PaymentIntent{}, models.Order{}, err
+	}
+
+	if intent.Provider != u.provider.Name() {
+		return models.PaymentIntent{}, models.Order{}, status.Errorf(codes.FailedPrecondition, "payment provider %s is not configured", intent.Provider)
+	}
+
+	return intent, order, nil
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	"github.com/situmorangbastian/skyros/orderservice/internal/repository"
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+)
+
+type PromotionUsecase interface {
+	StoreCoupon(ctx context.Context, coupon models.Coupon) (models.Coupon, error)
+	FetchCoupons(ctx context.Context) ([]models.Coupon, error)
+}
+
+type promotionUsecase struct {
+	promotionRepo repository.PromotionRepository
+}
+
+func NewPromotionUsecase(promotionRepo repository.PromotionRepository) PromotionUsecase {
+	return &promotionUsecase{
+		promotionRepo: promotionRepo,
+	}
+}
+
+// StoreCoupon creates a coupon owned by the calling seller.
+func (u *promotionUsecase) StoreCoupon(ctx context.Context, coupon models.Coupon) (models.Coupon, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.promotion.StoreCoupon").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.Coupon{}, err
+	}
+
+	coupon.SellerID = user.ID
+	coupon.Code = models.NormalizeCouponCode(coupon.Code)
+
+	result, err := u.promotionRepo.StoreCoupon(ctx, coupon)
+	if err != nil {
+		if err == repository.ErrCouponExists {
+			return models.Coupon{}, status.Error(codes.AlreadyExists, "coupon code already exists")
+		}
+		log.Error().Err(err).Msg("failed StoreCoupon")
+		return models.Coupon{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return result, nil
+}
+
+// FetchCoupons lists the calling seller's coupons, newest first.
+func (u *promotionUsecase) FetchCoupons(ctx context.Context) ([]models.Coupon, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.promotion.FetchCoupons").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := u.promotionRepo.FetchCoupons(ctx, user.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed FetchCoupons")
+		return nil, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/situmorangbastian/skyros/orderservice/internal/models"
+	"github.com/situmorangbastian/skyros/orderservice/internal/repository"
+	orderpb "github.com/situmorangbastian/skyros/proto/order"
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+)
+
+type ShipmentUsecase interface {
+	Create(ctx context.Context, shipment models.Shipment) (models.Shipment, error)
+	Get(ctx context.Context, ID string) (models.Shipment, error)
+	Fetch(ctx context.Context, orderID string) ([]models.Shipment, error)
+	Track(ctx context.Context, shipmentID string, event models.TrackingEvent) (models.Shipment, error)
+}
+
+type shipmentUsecase struct {
+	shipmentRepo repository.ShipmentRepository
+	orderRepo    repository.OrderRepository
+	userClient   auth.UserClient
+}
+
+func NewShipmentUsecase(shipmentRepo repository.ShipmentRepository, orderRepo repository.OrderRepository, userClient auth.UserClient) ShipmentUsecase {
+	return &shipmentUsecase{
+		shipmentRepo: shipmentRepo,
+		orderRepo:    orderRepo,
+		userClient:   userClient,
+	}
+}
+
+// Create ships items of an accepted order, by default every item not shipped yet.
+func (u *shipmentUsecase) Create(ctx context.Context, shipment models.Shipment) (models.Shipment, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.shipment.Create").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	filter, err := orderFilter(*user, shipment.OrderID)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	order, err := fetchOrder(ctx, u.orderRepo, filter)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	if order.Status != orderpb.OrderStatus_ORDER_STATUS_ACCEPTED {
+		return models.Shipment{}, status.Errorf(codes.FailedPrecondition, "cannot ship an order in status %s", order.Status)
+	}
+
+	shipment.Items, err = u.packageItems(ctx, order, shipment.Items)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	shipment.DestinationAddress = order.DestinationAddress
+	if shipment.SourceAddress == "" {
+		shipment.SourceAddress = order.SourceAddress
+	}
+	if shipment.SourceAddress == "" {
+		shipment.SourceAddress, err = u.sellerAddress(ctx, order.Seller.ID)
+		if err != nil {
+			return models.Shipment{}, err
+		}
+	}
+
+	result, err := u.shipmentRepo.StoreShipment(ctx, shipment, *user)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return models.Shipment{}, status.Error(codes.FailedPrecondition, "order status has been changed")
+		case repository.ErrShipmentExceedsOrder:
+			return models.Shipment{}, status.Error(codes.FailedPrecondition, err.Error())
+		case repository.ErrShipmentExists:
+			return models.Shipment{}, status.Error(codes.AlreadyExists, err.Error())
+		}
+		log.Error().Err(err).Msg("failed StoreShipment")
+		return models.Shipment{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return result, nil
+}
+
+func (u *shipmentUsecase) Get(ctx context.Context, ID string) (models.Shipment, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.shipment.Get").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	shipment, err := u.shipmentRepo.GetShipment(ctx, ID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return models.Shipment{}, status.Error(codes.NotFound, "Not Found")
+		}
+		log.Error().Err(err).Msg("failed GetShipment")
+		return models.Shipment{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	filter, err := orderFilter(*user, shipment.OrderID)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	if _, err := fetchOrder(ctx, u.orderRepo, filter); err != nil {
+		return models.Shipment{}, err
+	}
+
+	return shipment, nil
+}
+
+// Fetch returns the shipments of an order visible to the caller.
+func (u *shipmentUsecase) Fetch(ctx context.Context, orderID string) ([]models.Shipment, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.shipment.Fetch").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := orderFilter(*user, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fetchOrder(ctx, u.orderRepo, filter); err != nil {
+		return nil, err
+	}
+
+	result, err := u.shipmentRepo.FetchShipments(ctx, orderID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed FetchShipments")
+		return nil, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return result, nil
+}
+
+// Track records a carrier update on a shipment of one of the seller's orders.
+func (u *shipmentUsecase) Track(ctx context.Context, shipmentID string, event models.TrackingEvent) (models.Shipment, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.shipment.Track").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	shipment, err := u.Get(ctx, shipmentID)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	if !models.CanTrackShipment(shipment.Status, event.Status) {
+		return models.Shipment{}, status.Errorf(codes.FailedPrecondition, "cannot record %s on a shipment in status %s", event.Status, shipment.Status)
+	}
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	err = u.shipmentRepo.AddTrackingEvent(ctx, shipment.ID, shipment.Status, event, *user)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return models.Shipment{}, status.Error(codes.FailedPrecondition, "shipment status has been changed")
+		}
+		log.Error().Err(err).Msg("failed AddTrackingEvent")
+		return models.Shipment{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return u.Get(ctx, shipment.ID)
+}
+
+// packageItems checks the requested items against the items of order left to ship,
+// merging repeated products. No items means everything left to ship.
+func (u *shipmentUsecase) packageItems(ctx context.Context, order models.Order, items []models.ShipmentItem) ([]models.ShipmentItem, error) {
+	log := zerolog.Ctx(ctx)
+
+	shipments, err := u.shipmentRepo.FetchShipments(ctx, order.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed FetchShipments")
+		return nil, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	remaining := map[string]int64{}
+	productIds := []string{}
+	for _, item := range order.Items {
+		if _, ok := remaining[item.ProductID]; !ok {
+			productIds = append(productIds, item.ProductID)
+		}
+		remaining[item.ProductID] += item.Quantity
+	}
+	for _, shipment := range shipments {
+		for _, item := range shipment.Items {
+			remaining[item.ProductID] -= item.Quantity
+		}
+	}
+
+	result := []models.ShipmentItem{}
+	if len(items) == 0 {
+		for _, productID := range productIds {
+			if remaining[productID] > 0 {
+				result = append(result, models.ShipmentItem{
+					ProductID: productID,
+					Quantity:  remaining[productID],
+				})
+			}
+		}
+		if len(result) == 0 {
+			return nil, status.Error(codes.FailedPrecondition, "every item of the order is already shipped")
+		}
+		return result, nil
+	}
+
+	index := map[string]int{}
+	for _, item := range items {
+		if _, ok := remaining[item.ProductID]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "product %s is not in the order", item.ProductID)
+		}
+
+		if i, ok := index[item.ProductID]; ok {
+			result[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.ProductID] = len(result)
+		result = append(result, item)
+	}
+
+	for _, item := range result {
+		if item.Quantity > remaining[item.ProductID] {
+			return nil, status.Errorf(codes.FailedPrecondition, "only %d of product %s left to ship", remaining[item.ProductID], item.ProductID)
+		}
+	}
+
+	return result, nil
+}
+
+// sellerAddress returns the address stored on the seller's profile.
+func (u *shipmentUsecase) sellerAddress(ctx context.Context, sellerID string) (string, error) {
+	log := zerolog.Ctx(ctx)
+
+	users, err := u.userClient.FetchByIDs(ctx, []string{sellerID})
+	if err != nil {
+		log.Error().Err(err).Msg("failed FetchByIDs")
+		return "", status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	if users[sellerID].Address == "" {
+		return "", status.Error(codes.FailedPrecondition, "source_address is required: the seller has no address")
+	}
+
+	return users[sellerID].Address, nil
+}
@@ -25,15 +25,24 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/encoding/protojson"
 
+	"github.com/situmorangbastian/skyros/orderservice/internal/integration"
+	"github.com/situmorangbastian/skyros/orderservice/internal/integration/fakepay"
 	grpcClient "github.com/situmorangbastian/skyros/orderservice/internal/integration/grpc"
 	"github.com/situmorangbastian/skyros/orderservice/internal/repository/postgresql"
 	"github.com/situmorangbastian/skyros/orderservice/internal/service"
 	"github.com/situmorangbastian/skyros/orderservice/internal/usecase"
+	cartpb "github.com/situmorangbastian/skyros/proto/cart"
 	orderpb "github.com/situmorangbastian/skyros/proto/order"
+	paymentpb "github.com/situmorangbastian/skyros/proto/payment"
 	productpb "github.com/situmorangbastian/skyros/proto/product"
+	promotionpb "github.com/situmorangbastian/skyros/proto/promotion"
+	shipmentpb "github.com/situmorangbastian/skyros/proto/shipment"
 	userpb "github.com/situmorangbastian/skyros/proto/user"
 	"github.com/situmorangbastian/skyros/serviceutils"
 	"github.com/situmorangbastian/skyros/serviceutils/auth"
+	"github.com/situmorangbastian/skyros/serviceutils/idempotency"
+	"github.com/situmorangbastian/skyros/serviceutils/outbox"
+	"github.com/situmorangbastian/skyros/serviceutils/pagination"
 )
 
 func main() {
@@ -63,7 +72,7 @@ func main() {
 		}).With().Timestamp().Str("service", "orderservice").Caller().Logger()
 	}
 
-	required := []string{"DATABASE_URL", "SECRET_KEY", "GRPC_SERVER_PORT", "GRPC_SERVICE_ENDPOINT", "USER_SERVICE_GRPC", "PRODUCT_SERVICE_GRPC"}
+	required := []string{"DATABASE_URL", "SERVICE_KEY_FILE", "SERVICE_TRUSTED_KEYS_DIR", "PAGE_TOKEN_SECRET", "GRPC_SERVER_PORT", "GRPC_SERVICE_ENDPOINT", "USER_SERVICE_GRPC", "PRODUCT_SERVICE_GRPC", "PAYMENT_WEBHOOK_SECRET"}
 	for _, key := range required {
 		if cfg.GetString(key) == "" {
 			log.Fatal().Str("key", key).Msg("missing required config")
@@ -87,10 +96,29 @@ func main() {
 	}
 	log.Info().Msg("migrations applied successfully")
 
+	serviceKey, err := auth.ReadPrivateKey(cfg.GetString("SERVICE_KEY_FILE"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load service key")
+	}
+	serviceTokens := auth.NewServiceTokenSource("orderservice", serviceKey)
+
+	trustedServiceKeys, err := auth.ReadPublicKeys(cfg.GetString("SERVICE_TRUSTED_KEYS_DIR"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load trusted service keys")
+	}
+
+	identities, err := auth.NewIdentityVerifier(trustedServiceKeys, "gatewayservice")
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load identity issuers")
+	}
+
 	userConn, err := grpc.NewClient(
 		cfg.GetString("USER_SERVICE_GRPC"),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithUnaryInterceptor(serviceutils.CorrelationClientInterceptor()),
+		grpc.WithChainUnaryInterceptor(
+			serviceutils.CorrelationClientInterceptor(),
+			auth.ServiceClientInterceptor(serviceTokens),
+		),
 	)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to connect user service")
@@ -100,7 +128,10 @@ func main() {
 	productConn, err := grpc.NewClient(
 		cfg.GetString("PRODUCT_SERVICE_GRPC"),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithUnaryInterceptor(serviceutils.CorrelationClientInterceptor()),
+		grpc.WithChainUnaryInterceptor(
+			serviceutils.CorrelationClientInterceptor(),
+			auth.ServiceClientInterceptor(serviceTokens),
+		),
 	)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to init product service client")
@@ -110,17 +141,73 @@ func main() {
 	userClient := grpcClient.NewUserClient(userSvcClient)
 	productClient := grpcClient.NewProductClient(productSvcClient)
 	orderRepo := postgresql.NewOrderRepository(dbpool)
-	orderUsecase := usecase.NewUsecase(orderRepo, userClient, productClient, log.Logger)
+	promotionRepo := postgresql.NewPromotionRepository(dbpool)
+	orderUsecase := usecase.NewUsecase(orderRepo, promotionRepo, userClient, grpcClient.NewAddressClient(userSvcClient), productClient, log.Logger)
+	promotionUsecase := usecase.NewPromotionUsecase(promotionRepo)
+
+	cartTTL := cfg.GetDuration("CART_TTL")
+	if cartTTL <= 0 {
+		cartTTL = 72 * time.Hour
+	}
+	cartUsecase := usecase.NewCartUsecase(postgresql.NewCartRepository(dbpool), orderUsecase, productClient, cartTTL)
+
+	var paymentProvider integration.PaymentProvider
+	switch cfg.GetString("PAYMENT_PROVIDER") {
+	case "", fakepay.Name:
+		paymentProvider = fakepay.New(cfg.GetString("PAYMENT_WEBHOOK_SECRET"))
+	default:
+		log.Fatal().Str("provider", cfg.GetString("PAYMENT_PROVIDER")).Msg("unknown payment provider")
+	}
+	paymentUsecase := usecase.NewPaymentUsecase(postgresql.NewPaymentRepository(dbpool), orderRepo, paymentProvider)
+	shipmentUsecase := usecase.NewShipmentUsecase(postgresql.NewShipmentRepository(dbpool), orderRepo, userClient)
 
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			serviceutils.CorrelationServerInterceptorWithLogging(),
 			serviceutils.TraceErrors(),
-			auth.AuthInterceptor(cfg.GetString("SECRET_KEY"), userClient),
+			auth.ServiceAuthInterceptor(trustedServiceKeys, auth.ServiceACL{
+				paymentpb.PaymentService_HandlePaymentWebhook_FullMethodName: {"gatewayservice"},
+			}),
+			auth.AuthInterceptor(auth.NewKeySet(userSvcClient), userClient, identities),
+			auth.PolicyInterceptor(auth.Policy{
+				orderpb.OrderService_CreateOrder_FullMethodName:             {auth.UserBuyerType},
+				orderpb.OrderService_GetOrder_FullMethodName:                {auth.UserBuyerType, auth.UserSellerType, auth.UserAdminType},
+				orderpb.OrderService_GetOrders_FullMethodName:               {auth.UserBuyerType, auth.UserSellerType, auth.UserAdminType},
+				orderpb.OrderService_UpdateOrderStatus_FullMethodName:       {auth.UserSellerType, auth.UserAdminType},
+				orderpb.OrderService_CancelOrder_FullMethodName:             {auth.UserBuyerType, auth.UserAdminType},
+				orderpb.OrderService_RejectOrder_FullMethodName:             {auth.UserSellerType, auth.UserAdminType},
+				orderpb.OrderService_GetOrderTimeline_FullMethodName:        {auth.UserBuyerType, auth.UserSellerType, auth.UserAdminType},
+				promotionpb.PromotionService_CreateCoupon_FullMethodName:    {auth.UserSellerType},
+				promotionpb.PromotionService_GetCoupons_FullMethodName:      {auth.UserSellerType},
+				cartpb.CartService_GetCart_FullMethodName:                   {auth.UserBuyerType},
+				cartpb.CartService_AddCartItem_FullMethodName:               {auth.UserBuyerType},
+				cartpb.CartService_UpdateCartItem_FullMethodName:            {auth.UserBuyerType},
+				cartpb.CartService_RemoveCartItem_FullMethodName:            {auth.UserBuyerType},
+				cartpb.CartService_Checkout_FullMethodName:                  {auth.UserBuyerType},
+				paymentpb.PaymentService_CreatePaymentIntent_FullMethodName: {auth.UserBuyerType},
+				paymentpb.PaymentService_GetPaymentIntents_FullMethodName:   {auth.UserBuyerType, auth.UserSellerType, auth.UserAdminType},
+				paymentpb.PaymentService_CapturePayment_FullMethodName:      {auth.UserBuyerType, auth.UserAdminType},
+				paymentpb.PaymentService_RefundPayment_FullMethodName:       {auth.UserSellerType, auth.UserAdminType},
+				shipmentpb.ShipmentService_CreateShipment_FullMethodName:    {auth.UserSellerType, auth.UserAdminType},
+				shipmentpb.ShipmentService_GetShipments_FullMethodName:      {auth.UserBuyerType, auth.UserSellerType, auth.UserAdminType},
+				shipmentpb.ShipmentService_GetShipment_FullMethodName:       {auth.UserBuyerType, auth.UserSellerType, auth.UserAdminType},
+				shipmentpb.ShipmentService_AddTrackingEvent_FullMethodName:  {auth.UserSellerType, auth.UserAdminType},
+			}),
+			idempotency.Interceptor(
+				idempotency.NewPostgresStore(dbpool),
+				orderpb.OrderService_CreateOrder_FullMethodName,
+				cartpb.CartService_Checkout_FullMethodName,
+				paymentpb.PaymentService_CreatePaymentIntent_FullMethodName,
+				shipmentpb.ShipmentService_CreateShipment_FullMethodName,
+			),
 		),
 	)
-	orderService := service.NewOrderService(orderUsecase, serviceutils.NewCustomValidator(), log.Logger)
+	orderService := service.NewOrderService(orderUsecase, serviceutils.NewCustomValidator(), pagination.NewCodec(cfg.GetString("PAGE_TOKEN_SECRET")), log.Logger)
 	orderpb.RegisterOrderServiceServer(grpcServer, orderService)
+	promotionpb.RegisterPromotionServiceServer(grpcServer, service.NewPromotionService(promotionUsecase))
+	cartpb.RegisterCartServiceServer(grpcServer, service.NewCartService(cartUsecase, serviceutils.NewCustomValidator()))
+	paymentpb.RegisterPaymentServiceServer(grpcServer, service.NewPaymentService(paymentUsecase))
+	shipmentpb.RegisterShipmentServiceServer(grpcServer, service.NewShipmentService(shipmentUsecase, serviceutils.NewCustomValidator()))
 
 	mux := runtime.NewServeMux(
 		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
@@ -132,6 +219,7 @@ func main() {
 			},
 		}),
 		runtime.WithErrorHandler(serviceutils.NewRestErrorHandler()),
+		runtime.WithIncomingHeaderMatcher(idempotency.HeaderMatcher),
 	)
 	if err := orderpb.RegisterOrderServiceHandlerFromEndpoint(
 		context.Background(),
@@ -141,6 +229,38 @@ func main() {
 	); err != nil {
 		log.Fatal().Err(err).Msg("failed to register gRPC-Gateway")
 	}
+	if err := promotionpb.RegisterPromotionServiceHandlerFromEndpoint(
+		context.Background(),
+		mux,
+		cfg.GetString("GRPC_SERVICE_ENDPOINT"),
+		[]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+	); err != nil {
+		log.Fatal().Err(err).Msg("failed to register gRPC-Gateway")
+	}
+	if err := cartpb.RegisterCartServiceHandlerFromEndpoint(
+		context.Background(),
+		mux,
+		cfg.GetString("GRPC_SERVICE_ENDPOINT"),
+		[]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+	); err != nil {
+		log.Fatal().Err(err).Msg("failed to register gRPC-Gateway")
+	}
+	if err := paymentpb.RegisterPaymentServiceHandlerFromEndpoint(
+		context.Background(),
+		mux,
+		cfg.GetString("GRPC_SERVICE_ENDPOINT"),
+		[]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+	); err != nil {
+		log.Fatal().Err(err).Msg("failed to register gRPC-Gateway")
+	}
+	if err := shipmentpb.RegisterShipmentServiceHandlerFromEndpoint(
+		context.Background(),
+		mux,
+		cfg.GetString("GRPC_SERVICE_ENDPOINT"),
+		[]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+	); err != nil {
+		log.Fatal().Err(err).Msg("failed to register gRPC-Gateway")
+	}
 
 	restServer := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.GetInt("GRPC_GATEWAY_SERVER_PORT")),
@@ -149,6 +269,14 @@ func main() {
 
 	wg := sync.WaitGroup{}
 
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		log.Info().Msg("outbox relay starting")
+		outbox.NewRelay(dbpool, outbox.NewPostgresPublisher(dbpool)).Run(relayCtx)
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -187,6 +315,7 @@ func main() {
 	}
 
 	grpcServer.GracefulStop()
+	stopRelay()
 
 	if err := userConn.Close(); err != nil {
 		log.Error().Err(err).Msg("failed to close user service gRPC connection")
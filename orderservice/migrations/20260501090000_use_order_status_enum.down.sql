@@ -0,0 +1 @@
+UPDATE orders SET status = status - 1 WHERE status IN (1, 2);
@@ -0,0 +1,3 @@
+-- Shift the legacy 0 (pending) / 1 (accepted) values onto the OrderStatus enum,
+-- where 0 is reserved for ORDER_STATUS_UNSPECIFIED.
+UPDATE orders SET status = status + 1;
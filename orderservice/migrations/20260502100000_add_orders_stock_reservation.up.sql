@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS stock_reservation_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS checkout_id;
+DROP TABLE IF EXISTS checkouts;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS checkouts (
+    id UUID PRIMARY KEY,
+    buyer_id UUID NOT NULL,
+    total_price BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS checkout_id UUID REFERENCES checkouts (id);
+
+CREATE INDEX IF NOT EXISTS orders_checkout_id_idx ON orders (checkout_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS orders_created_at_id_idx;
+
+ALTER TABLE orders ALTER COLUMN created_at DROP NOT NULL;
@@ -0,0 +1,4 @@
+UPDATE orders SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE orders ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS orders_created_at_id_idx ON orders (created_at DESC, id DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS orders_products_product_id_idx;
+DROP INDEX IF EXISTS orders_seller_id_created_at_idx;
+DROP INDEX IF EXISTS orders_buyer_id_created_at_idx;
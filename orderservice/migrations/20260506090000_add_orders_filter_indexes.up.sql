@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS orders_buyer_id_created_at_idx ON orders (buyer_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS orders_seller_id_created_at_idx ON orders (seller_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS orders_products_product_id_idx ON orders_products (product_id, order_id);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders (id),
+    from_status SMALLINT NOT NULL,
+    to_status SMALLINT NOT NULL,
+    actor_id UUID NOT NULL,
+    actor_type VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS order_status_history_order_id_idx ON order_status_history (order_id, created_at);
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL UNIQUE REFERENCES orders (id),
+    amount BIGINT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
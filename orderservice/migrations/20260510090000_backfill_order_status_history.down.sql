@@ -0,0 +1 @@
+DELETE FROM order_status_history WHERE from_status = 0;
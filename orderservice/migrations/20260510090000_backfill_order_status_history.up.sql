@@ -0,0 +1,8 @@
+-- Every order's timeline starts at its creation. Orders placed before the history existed
+-- get that first entry even when they already changed status since.
+INSERT INTO order_status_history (id, order_id, from_status, to_status, actor_id, actor_type, reason, created_at)
+SELECT gen_random_uuid(), o.id, 0, 1, o.buyer_id, 'buyer', '', o.created_at
+FROM orders o
+WHERE NOT EXISTS (
+    SELECT 1 FROM order_status_history h WHERE h.order_id = o.id AND h.from_status = 0
+);
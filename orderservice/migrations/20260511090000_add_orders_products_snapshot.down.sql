@@ -0,0 +1,5 @@
+ALTER TABLE orders_products
+    DROP COLUMN IF EXISTS seller_name,
+    DROP COLUMN IF EXISTS seller_id,
+    DROP COLUMN IF EXISTS unit_price,
+    DROP COLUMN IF EXISTS product_name;
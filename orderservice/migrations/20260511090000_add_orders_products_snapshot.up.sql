@@ -0,0 +1,11 @@
+ALTER TABLE orders_products
+    ADD COLUMN IF NOT EXISTS product_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS unit_price BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS seller_id UUID,
+    ADD COLUMN IF NOT EXISTS seller_name TEXT NOT NULL DEFAULT '';
+
+-- Every item of an order belongs to the order's seller. Names and prices of items
+-- placed before snapshots existed are unknown and stay empty.
+UPDATE orders_products SET seller_id = orders.seller_id
+FROM orders
+WHERE orders.id = orders_products.order_id AND orders_products.seller_id IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE refunds DROP COLUMN IF EXISTS currency;
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
+ALTER TABLE checkouts DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,9 @@
+-- Totals stored before currencies existed are taken to be IDR minor units.
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE checkouts ALTER COLUMN currency DROP DEFAULT;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE orders ALTER COLUMN currency DROP DEFAULT;
+
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE refunds ALTER COLUMN currency DROP DEFAULT;
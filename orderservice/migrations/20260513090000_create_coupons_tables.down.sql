@@ -0,0 +1,3 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS subtotal;
+DROP TABLE IF EXISTS order_discounts;
+DROP TABLE IF EXISTS coupons;
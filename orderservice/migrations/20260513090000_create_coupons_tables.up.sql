@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS coupons (
+    id UUID PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    seller_id UUID NOT NULL,
+    discount_type SMALLINT NOT NULL,
+    percent_off INTEGER NOT NULL DEFAULT 0,
+    amount_off BIGINT NOT NULL DEFAULT 0,
+    min_order_value BIGINT NOT NULL DEFAULT 0,
+    -- Currency of amount_off and min_order_value, empty when neither is set.
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP DEFAULT NULL,
+    max_redemptions BIGINT NOT NULL DEFAULT 0,
+    max_redemptions_per_buyer BIGINT NOT NULL DEFAULT 0,
+    redemptions BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS coupons_seller_id_idx ON coupons (seller_id, created_at);
+
+CREATE TABLE IF NOT EXISTS order_discounts (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders (id),
+    coupon_id UUID NOT NULL REFERENCES coupons (id),
+    buyer_id UUID NOT NULL,
+    code VARCHAR(64) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS order_discounts_order_id_idx ON order_discounts (order_id);
+CREATE INDEX IF NOT EXISTS order_discounts_coupon_buyer_idx ON order_discounts (coupon_id, buyer_id);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS subtotal BIGINT;
+UPDATE orders SET subtotal = total_price WHERE subtotal IS NULL;
+ALTER TABLE orders ALTER COLUMN subtotal SET NOT NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS carts (
+    buyer_id UUID PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS carts_expires_at_idx ON carts (expires_at);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    buyer_id UUID NOT NULL REFERENCES carts (buyer_id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    quantity BIGINT NOT NULL,
+    unit_price BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (buyer_id, product_id)
+);
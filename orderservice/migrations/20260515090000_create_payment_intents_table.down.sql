@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS payment_webhook_events;
+DROP TABLE IF EXISTS payment_intents;
+ALTER TABLE orders DROP COLUMN IF EXISTS payment_status;
@@ -0,0 +1,29 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_status SMALLINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS payment_intents (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders (id),
+    buyer_id UUID NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    provider_reference VARCHAR(255) NOT NULL DEFAULT '',
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status SMALLINT NOT NULL,
+    failure_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS payment_intents_order_id_idx ON payment_intents (order_id, created_at);
+
+-- An order has at most one payment that is processing, awaiting capture or succeeded.
+CREATE UNIQUE INDEX IF NOT EXISTS payment_intents_order_id_active_idx ON payment_intents (order_id) WHERE status IN (1, 2, 3);
+
+CREATE UNIQUE INDEX IF NOT EXISTS payment_intents_provider_reference_idx ON payment_intents (provider, provider_reference) WHERE provider_reference <> '';
+
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+    provider VARCHAR(32) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, event_id)
+);
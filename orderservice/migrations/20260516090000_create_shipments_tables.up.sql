@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS shipments (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders (id),
+    carrier VARCHAR(64) NOT NULL,
+    tracking_number VARCHAR(128) NOT NULL,
+    source_address TEXT NOT NULL,
+    destination_address TEXT NOT NULL,
+    status SMALLINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (carrier, tracking_number)
+);
+
+CREATE INDEX IF NOT EXISTS shipments_order_id_idx ON shipments (order_id, created_at);
+
+CREATE TABLE IF NOT EXISTS shipment_items (
+    shipment_id UUID NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    quantity BIGINT NOT NULL,
+    PRIMARY KEY (shipment_id, product_id)
+);
+
+CREATE TABLE IF NOT EXISTS shipment_events (
+    id UUID PRIMARY KEY,
+    shipment_id UUID NOT NULL REFERENCES shipments (id) ON DELETE CASCADE,
+    status SMALLINT NOT NULL,
+    location TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS shipment_events_shipment_id_idx ON shipment_events (shipment_id, occurred_at);
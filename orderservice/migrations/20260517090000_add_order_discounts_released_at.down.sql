@@ -0,0 +1,7 @@
+UPDATE coupons c SET redemptions = c.redemptions + released.uses, updated_at = NOW()
+FROM (
+    SELECT coupon_id, COUNT(*) AS uses FROM order_discounts WHERE released_at IS NOT NULL GROUP BY coupon_id
+) released
+WHERE c.id = released.coupon_id;
+
+ALTER TABLE order_discounts DROP COLUMN IF EXISTS released_at;
@@ -0,0 +1,12 @@
+-- Coupon uses of cancelled and rejected orders are given back.
+ALTER TABLE order_discounts ADD COLUMN IF NOT EXISTS released_at TIMESTAMP DEFAULT NULL;
+
+UPDATE order_discounts d SET released_at = o.updated_at
+FROM orders o
+WHERE o.id = d.order_id AND o.status IN (5, 6) AND d.released_at IS NULL;
+
+UPDATE coupons c SET redemptions = GREATEST(c.redemptions - released.uses, 0), updated_at = NOW()
+FROM (
+    SELECT coupon_id, COUNT(*) AS uses FROM order_discounts WHERE released_at IS NOT NULL GROUP BY coupon_id
+) released
+WHERE c.id = released.coupon_id;
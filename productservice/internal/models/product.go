@@ -4,22 +4,86 @@ import (
 	"time"
 
 	"github.com/situmorangbastian/skyros/serviceutils/auth"
+	"github.com/situmorangbastian/skyros/serviceutils/money"
+	"github.com/situmorangbastian/skyros/serviceutils/pagination"
 )
 
 type Product struct {
 	ID          string      `json:"id"`
 	Name        string      `json:"name" validate:"required"`
 	Description string      `json:"description" validate:"required"`
-	Price       int64       `json:"price" validate:"required"`
+	Price       money.Money `json:"price" validate:"-"`
+	Stock       int64       `json:"stock" validate:"min=0"`
 	Seller      auth.Claims `json:"seller" validate:"-"`
 	CreatedTime time.Time   `json:"created_time"`
 	UpdatedTime time.Time   `json:"updated_time"`
+	// SearchRank is the full-text relevance of the product, set when fetching with a search.
+	SearchRank float32 `json:"-"`
 }
 
+type ProductSort int
+
+const (
+	ProductSortNewest ProductSort = iota
+	ProductSortRelevance
+	ProductSortPriceAsc
+	ProductSortPriceDesc
+)
+
 type ProductFilter struct {
-	Page     int
+	// After is the last product of the previous page, nil for the first page.
+	After    *pagination.Cursor
 	PageSize int
 	Search   string
 	SellerID string
 	OrderID  string
+	// MinPrice and MaxPrice are inclusive bounds in minor units, nil when unbounded.
+	MinPrice     *int64
+	MaxPrice     *int64
+	CurrencyCode string
+	Sort         ProductSort
+}
+
+// PriceRange is a price facet bucket from Min (inclusive) to Max (exclusive). A zero
+// Max leaves the range open-ended.
+type PriceRange struct {
+	Min int64
+	Max int64
 }
+
+// PriceFacetRanges are the buckets products are counted in for the price facet.
+var PriceFacetRanges = []PriceRange{
+	{Min: 0, Max: 50000},
+	{Min: 50000, Max: 100000},
+	{Min: 100000, Max: 500000},
+	{Min: 500000, Max: 1000000},
+	{Min: 1000000},
+}
+
+type PriceRangeCount struct {
+	PriceRange
+	Count int64
+}
+
+type SellerCount struct {
+	SellerID string
+	Count    int64
+}
+
+type ProductFacets struct {
+	PriceRanges []PriceRangeCount
+	Sellers     []SellerCount
+}
+
+type StockItem struct {
+	ProductID string `json:"product_id" validate:"required"`
+	Quantity  int64  `json:"quantity" validate:"min=1"`
+}
+
+type StockReservationStatus int
+
+const (
+	StockReserved StockReservationStatus = iota + 1
+	StockCommitted
+	StockReleased
+)
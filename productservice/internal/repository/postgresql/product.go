@@ -2,20 +2,21 @@ package postgresql
 
 import (
 	"context"
-	"database/sql"
-	"fmt"
-	"regexp"
-	"strings"
+	"sort"
+	"strconv"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/situmorangbastian/skyros/productservice/internal/models"
 	"github.com/situmorangbastian/skyros/productservice/internal/repository"
+	eventspb "github.com/situmorangbastian/skyros/proto/events"
+	"github.com/situmorangbastian/skyros/serviceutils/outbox"
 )
 
 type productRepository struct {
@@ -35,27 +36,51 @@ func (r *productRepository) Store(ctx context.Context, product models.Product) (
 	product.CreatedTime = timeNow
 	product.UpdatedTime = timeNow
 
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return models.Product{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	query, args, err := psql.Insert("products").
-		Columns("id", "name", "description", "price", "seller_id", "created_at", "updated_at").
-		Values(product.ID, product.Name, product.Description, product.Price, product.Seller.ID, product.CreatedTime, product.UpdatedTime).ToSql()
+		Columns("id", "name", "description", "price", "currency", "stock", "seller_id", "created_at", "updated_at").
+		Values(product.ID, product.Name, product.Description, product.Price.MinorUnits, product.Price.CurrencyCode, product.Stock, product.Seller.ID, product.CreatedTime, product.UpdatedTime).ToSql()
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	_, err = tx.Exec(ctx, query, args...)
 	if err != nil {
 		return models.Product{}, err
 	}
 
-	_, err = r.dbpool.Exec(ctx, query, args...)
+	err = outbox.Write(ctx, tx, product.ID, &eventspb.ProductStored{
+		ProductId: product.ID,
+		SellerId:  product.Seller.ID,
+		Name:      product.Name,
+		Price:     product.Price.Proto(),
+		Stock:     product.Stock,
+	})
 	if err != nil {
 		return models.Product{}, err
 	}
 
+	if err = tx.Commit(ctx); err != nil {
+		return models.Product{}, err
+	}
+
 	return product, nil
 }
 
 func (r *productRepository) Get(ctx context.Context, ID string) (models.Product, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	query, args, err := psql.Select("id", "name", "description", "price", "seller_id", "created_at", "updated_at").
+	query, args, err := psql.Select("id", "name", "description", "price", "currency", "stock", "seller_id", "created_at", "updated_at").
 		From("products").
-		Where(sq.Eq{"id": ID}).ToSql()
+		Where(sq.Eq{"id": ID}).
+		Where("deleted_at IS NULL").ToSql()
 	if err != nil {
 		return models.Product{}, err
 	}
@@ -66,13 +91,15 @@ func (r *productRepository) Get(ctx context.Context, ID string) (models.Product,
 		&product.ID,
 		&product.Name,
 		&product.Description,
-		&product.Price,
+		&product.Price.MinorUnits,
+		&product.Price.CurrencyCode,
+		&product.Stock,
 		&product.Seller.ID,
 		&product.CreatedTime,
 		&product.UpdatedTime,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if err == pgx.ErrNoRows {
 			return models.Product{}, status.Error(codes.NotFound, "product not found")
 		}
 		return models.Product{}, err
@@ -81,28 +108,56 @@ func (r *productRepository) Get(ctx context.Context, ID string) (models.Product,
 	return product, nil
 }
 
+// searchQuery parses user input with websearch syntax: quoted phrases, "or" and -exclusions.
+const searchQuery = "websearch_to_tsquery('simple', ?)"
+
 func (r *productRepository) Fetch(ctx context.Context, filter models.ProductFilter) ([]models.Product, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	qBuilder := psql.Select("id", "name", "description", "price", "seller_id", "created_at", "updated_at").
-		From("products").
-		Where("deleted_at IS NULL").
-		OrderBy("created_at DESC")
 
-	offset := (filter.Page - 1) * filter.PageSize
-	qBuilder = qBuilder.Limit(uint64(filter.PageSize))
-	if offset > 0 {
-		qBuilder.Offset(uint64(offset))
+	rank := sq.Expr("0::real")
+	if filter.Search != "" {
+		rank = sq.Expr("ts_rank(search_vector, "+searchQuery+")", filter.Search)
 	}
 
-	if filter.Search != "" {
-		keywords := strings.Split(filter.Search, ",")
-		for _, keyword := range keywords {
-			qBuilder = qBuilder.Where(sq.Like{"name": fmt.Sprintf("%%%v%%", regexp.QuoteMeta(keyword))})
-		}
+	qBuilder := psql.Select("id", "name", "description", "price", "currency", "stock", "seller_id", "created_at", "updated_at").
+		Column(sq.Alias(rank, "rank")).
+		From("products").
+		Limit(uint64(filter.PageSize))
+	qBuilder = applyProductFilter(qBuilder, filter)
+
+	productSort := filter.Sort
+	if productSort == models.ProductSortRelevance && filter.Search == "" {
+		productSort = models.ProductSortNewest
 	}
 
-	if filter.SellerID != "" {
-		qBuilder = qBuilder.Where(sq.Eq{"seller_id": filter.SellerID})
+	switch productSort {
+	case models.ProductSortRelevance:
+		qBuilder = qBuilder.OrderBy("rank DESC", "id DESC")
+		if filter.After != nil {
+			afterRank, err := strconv.ParseFloat(filter.After.Key, 32)
+			if err != nil {
+				return []models.Product{}, err
+			}
+			qBuilder = qBuilder.Where("(ts_rank(search_vector, "+searchQuery+"), id) < (?::real, ?)", filter.Search, float32(afterRank), filter.After.ID)
+		}
+	case models.ProductSortPriceAsc, models.ProductSortPriceDesc:
+		direction, comparison := "ASC", ">"
+		if productSort == models.ProductSortPriceDesc {
+			direction, comparison = "DESC", "<"
+		}
+		qBuilder = qBuilder.OrderBy("price "+direction, "id "+direction)
+		if filter.After != nil {
+			afterPrice, err := strconv.ParseInt(filter.After.Key, 10, 64)
+			if err != nil {
+				return []models.Product{}, err
+			}
+			qBuilder = qBuilder.Where("(price, id) "+comparison+" (?, ?)", afterPrice, filter.After.ID)
+		}
+	default:
+		qBuilder = qBuilder.OrderBy("created_at DESC", "id DESC")
+		if filter.After != nil {
+			qBuilder = qBuilder.Where("(created_at, id) < (?, ?)", filter.After.CreatedAt, filter.After.ID)
+		}
 	}
 
 	query, args, err := qBuilder.ToSql()
@@ -114,6 +169,7 @@ func (r *productRepository) Fetch(ctx context.Context, filter models.ProductFilt
 	if err != nil {
 		return []models.Product{}, err
 	}
+	defer rows.Close()
 
 	products := make([]models.Product, 0)
 	for rows.Next() {
@@ -122,10 +178,13 @@ func (r *productRepository) Fetch(ctx context.Context, filter models.ProductFilt
 			&product.ID,
 			&product.Name,
 			&product.Description,
-			&product.Price,
+			&product.Price.MinorUnits,
+			&product.Price.CurrencyCode,
+			&product.Stock,
 			&product.Seller.ID,
 			&product.CreatedTime,
 			&product.UpdatedTime,
+			&product.SearchRank,
 		)
 		if err != nil {
 			return []models.Product{}, err
@@ -134,14 +193,131 @@ func (r *productRepository) Fetch(ctx context.Context, filter models.ProductFilt
 		products = append(products, product)
 	}
 
-	return products, nil
+	return products, rows.Err()
+}
+
+// Facets counts the products matching filter per price range and per seller. Prices in
+// different currencies cannot be compared, so price ranges are only counted when filter
+// has a currency. Pagination and sorting in filter are ignored.
+func (r *productRepository) Facets(ctx context.Context, filter models.ProductFilter) (models.ProductFacets, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	facets := models.ProductFacets{
+		PriceRanges: make([]models.PriceRangeCount, 0, len(models.PriceFacetRanges)),
+		Sellers:     make([]models.SellerCount, 0),
+	}
+
+	if filter.CurrencyCode != "" {
+		priceRanges, err := r.priceRangeFacets(ctx, filter)
+		if err != nil {
+			return models.ProductFacets{}, err
+		}
+		facets.PriceRanges = priceRanges
+	}
+
+	sellerBuilder := psql.Select("seller_id", "count(*)").
+		From("products").
+		GroupBy("seller_id").
+		OrderBy("count(*) DESC", "seller_id").
+		Limit(sellerFacetLimit)
+	sellerBuilder = applyProductFilter(sellerBuilder, filter)
+
+	query, args, err := sellerBuilder.ToSql()
+	if err != nil {
+		return models.ProductFacets{}, err
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return models.ProductFacets{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		seller := models.SellerCount{}
+		if err := rows.Scan(&seller.SellerID, &seller.Count); err != nil {
+			return models.ProductFacets{}, err
+		}
+		facets.Sellers = append(facets.Sellers, seller)
+	}
+
+	return facets, rows.Err()
+}
+
+func (r *productRepository) priceRangeFacets(ctx context.Context, filter models.ProductFilter) ([]models.PriceRangeCount, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	priceBuilder := psql.Select().From("products")
+	for _, priceRange := range models.PriceFacetRanges {
+		if priceRange.Max == 0 {
+			priceBuilder = priceBuilder.Column("count(*) FILTER (WHERE price >= ?)", priceRange.Min)
+			continue
+		}
+		priceBuilder = priceBuilder.Column("count(*) FILTER (WHERE price >= ? AND price < ?)", priceRange.Min, priceRange.Max)
+	}
+	priceBuilder = applyProductFilter(priceBuilder, filter)
+
+	query, args, err := priceBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]int64, len(models.PriceFacetRanges))
+	dest := make([]any, len(counts))
+	for index := range counts {
+		dest[index] = &counts[index]
+	}
+
+	if err := r.dbpool.QueryRow(ctx, query, args...).Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	priceRanges := make([]models.PriceRangeCount, 0, len(counts))
+	for index, priceRange := range models.PriceFacetRanges {
+		priceRanges = append(priceRanges, models.PriceRangeCount{
+			PriceRange: priceRange,
+			Count:      counts[index],
+		})
+	}
+	return priceRanges, nil
+}
+
+// sellerFacetLimit caps the seller facet to the sellers with the most matching products.
+const sellerFacetLimit = 20
+
+// applyProductFilter adds the conditions shared by Fetch and Facets.
+func applyProductFilter(qBuilder sq.SelectBuilder, filter models.ProductFilter) sq.SelectBuilder {
+	qBuilder = qBuilder.Where("deleted_at IS NULL")
+
+	if filter.Search != "" {
+		qBuilder = qBuilder.Where("search_vector @@ "+searchQuery, filter.Search)
+	}
+
+	if filter.SellerID != "" {
+		qBuilder = qBuilder.Where(sq.Eq{"seller_id": filter.SellerID})
+	}
+
+	if filter.CurrencyCode != "" {
+		qBuilder = qBuilder.Where(sq.Eq{"currency": filter.CurrencyCode})
+	}
+
+	if filter.MinPrice != nil {
+		qBuilder = qBuilder.Where(sq.GtOrEq{"price": *filter.MinPrice})
+	}
+
+	if filter.MaxPrice != nil {
+		qBuilder = qBuilder.Where(sq.LtOrEq{"price": *filter.MaxPrice})
+	}
+
+	return qBuilder
 }
 
 func (r *productRepository) FetchByIds(ctx context.Context, ids []string) (map[string]models.Product, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	qBuilder := psql.Select("id", "name", "description", "price", "seller_id", "created_at", "updated_at").
+	qBuilder := psql.Select("id", "name", "description", "price", "currency", "stock", "seller_id", "created_at", "updated_at").
 		From("products").
-		Where(sq.Eq{"id": ids})
+		Where(sq.Eq{"id": ids}).
+		Where("deleted_at IS NULL")
 
 	query, args, err := qBuilder.ToSql()
 	if err != nil {
@@ -160,7 +336,9 @@ func (r *productRepository) FetchByIds(ctx context.Context, ids []string) (map[s
 			&product.ID,
 			&product.Name,
 			&product.Description,
-			&product.Price,
+			&product.Price.MinorUnits,
+			&product.Price.CurrencyCode,
+			&product.Stock,
 			&product.Seller.ID,
 			&product.CreatedTime,
 			&product.UpdatedTime,
@@ -174,3 +352,261 @@ func (r *productRepository) FetchByIds(ctx context.Context, ids []string) (map[s
 
 	return products, nil
 }
+
+func (r *productRepository) Update(ctx context.Context, product models.Product, fields []string) (models.Product, error) {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return models.Product{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	qBuilder := psql.Update("products").
+		Set("updated_at", time.Now())
+	for _, field := range fields {
+		switch field {
+		case "name":
+			qBuilder = qBuilder.Set("name", product.Name)
+		case "description":
+			qBuilder = qBuilder.Set("description", product.Description)
+		case "price":
+			qBuilder = qBuilder.
+				Set("price", product.Price.MinorUnits).
+				Set("currency", product.Price.CurrencyCode)
+		case "stock":
+			qBuilder = qBuilder.Set("stock", product.Stock)
+		}
+	}
+
+	// Stock moves under reservations without the seller's read in between, so the
+	// write is a compare-and-set on the row version the seller edited.
+	query, args, err := qBuilder.
+		Where(sq.Eq{"id": product.ID, "updated_at": product.UpdatedTime}).
+		Where("deleted_at IS NULL").
+		Suffix("RETURNING name, description, price, currency, stock, updated_at").ToSql()
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	err = tx.QueryRow(ctx, query, args...).Scan(
+		&product.Name,
+		&product.Description,
+		&product.Price.MinorUnits,
+		&product.Price.CurrencyCode,
+		&product.Stock,
+		&product.UpdatedTime,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Product{}, status.Error(codes.Aborted, "product was changed by another request, retry the update")
+		}
+		return models.Product{}, err
+	}
+
+	err = outbox.Write(ctx, tx, product.ID, &eventspb.ProductUpdated{
+		ProductId: product.ID,
+		SellerId:  product.Seller.ID,
+		Name:      product.Name,
+		Price:     product.Price.Proto(),
+		Stock:     product.Stock,
+	})
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return models.Product{}, err
+	}
+
+	return product, nil
+}
+
+func (r *productRepository) Delete(ctx context.Context, ID string) error {
+	timeNow := time.Now()
+
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Update("products").
+		Set("deleted_at", timeNow).
+		Set("updated_at", timeNow).
+		Where(sq.Eq{"id": ID}).
+		Where("deleted_at IS NULL").ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return status.Error(codes.NotFound, "product not found")
+	}
+
+	if err = outbox.Write(ctx, tx, ID, &eventspb.ProductDeleted{ProductId: ID}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ReserveStock decrements the stock of every item in a single transaction and records
+// the reservation, so either the whole batch is reserved or none of it is.
+func (r *productRepository) ReserveStock(ctx context.Context, items []models.StockItem) (string, error) {
+	// Lock rows in a stable order so concurrent reservations cannot deadlock.
+	sorted := make([]models.StockItem, len(items))
+	copy(sorted, items)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ProductID < sorted[j].ProductID
+	})
+
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	timeNow := time.Now()
+	reservationID := uuid.New().String()
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	for _, item := range sorted {
+		query, args, err := psql.Update("products").
+			Set("stock", sq.Expr("stock - ?", item.Quantity)).
+			Set("updated_at", timeNow).
+			Where(sq.Eq{"id": item.ProductID}).
+			Where(sq.GtOrEq{"stock": item.Quantity}).
+			Where("deleted_at IS NULL").ToSql()
+		if err != nil {
+			return "", err
+		}
+
+		result, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return "", err
+		}
+
+		if result.RowsAffected() == 0 {
+			return "", status.Errorf(codes.FailedPrecondition, "insufficient stock for product %s", item.ProductID)
+		}
+
+		query, args, err = psql.Insert("stock_reservations").
+			Columns("id", "reservation_id", "product_id", "quantity", "status", "created_at", "updated_at").
+			Values(uuid.New().String(), reservationID, item.ProductID, item.Quantity, models.StockReserved, timeNow, timeNow).ToSql()
+		if err != nil {
+			return "", err
+		}
+
+		if _, err = tx.Exec(ctx, query, args...); err != nil {
+			return "", err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return "", err
+	}
+
+	return reservationID, nil
+}
+
+func (r *productRepository) CommitStock(ctx context.Context, reservationID string) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Update("stock_reservations").
+		Set("status", models.StockCommitted).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{
+			"reservation_id": reservationID,
+			"status":         models.StockReserved,
+		}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := r.dbpool.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return status.Error(codes.NotFound, "stock reservation not found")
+	}
+
+	return nil
+}
+
+// ReleaseStock returns the reserved quantities to the products. Releasing an already
+// released reservation is a no-op.
+func (r *productRepository) ReleaseStock(ctx context.Context, reservationID string) error {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	timeNow := time.Now()
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Update("stock_reservations").
+		Set("status", models.StockReleased).
+		Set("updated_at", timeNow).
+		Where(sq.Eq{
+			"reservation_id": reservationID,
+			"status":         []models.StockReservationStatus{models.StockReserved, models.StockCommitted},
+		}).
+		Suffix("RETURNING product_id, quantity").ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	items := make([]models.StockItem, 0)
+	for rows.Next() {
+		item := models.StockItem{}
+		if err = rows.Scan(&item.ProductID, &item.Quantity); err != nil {
+			rows.Close()
+			return err
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ProductID < items[j].ProductID
+	})
+
+	for _, item := range items {
+		query, args, err := psql.Update("products").
+			Set("stock", sq.Expr("stock + ?", item.Quantity)).
+			Set("updated_at", timeNow).
+			Where(sq.Eq{"id": item.ProductID}).ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.Exec(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -10,5 +10,14 @@ type ProductRepository interface {
 	Store(ctx context.Context, product models.Product) (models.Product, error)
 	Get(ctx context.Context, ID string) (models.Product, error)
 	Fetch(ctx context.Context, filter models.ProductFilter) ([]models.Product, error)
+	Facets(ctx context.Context, filter models.ProductFilter) (models.ProductFacets, error)
 	FetchByIds(ctx context.Context, ids []string) (map[string]models.Product, error)
+	// Update writes the fields of product named in fields, only if the product has not
+	// changed since it was read at product.UpdatedTime. It returns an Aborted status
+	// when it has, e.g. because stock was reserved in between.
+	Update(ctx context.Context, product models.Product, fields []string) (models.Product, error)
+	Delete(ctx context.Context, ID string) error
+	ReserveStock(ctx context.Context, items []models.StockItem) (string, error)
+	CommitStock(ctx context.Context, reservationID string) error
+	ReleaseStock(ctx context.Context, reservationID string) error
 }
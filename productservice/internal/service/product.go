@@ -2,24 +2,34 @@ package service
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
 	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
 	"github.com/situmorangbastian/skyros/productservice/internal/models"
 	"github.com/situmorangbastian/skyros/productservice/internal/usecase"
 	productpb "github.com/situmorangbastian/skyros/proto/product"
 	userpb "github.com/situmorangbastian/skyros/proto/user"
 	"github.com/situmorangbastian/skyros/serviceutils"
+	"github.com/situmorangbastian/skyros/serviceutils/money"
+	"github.com/situmorangbastian/skyros/serviceutils/pagination"
 )
 
 type handler struct {
 	productUsecase usecase.ProductUsecase
 	validators     serviceutils.CustomValidator
+	pageTokens     *pagination.Codec
 }
 
-func NewProductService(productUsecase usecase.ProductUsecase, validators serviceutils.CustomValidator) productpb.ProductServiceServer {
+func NewProductService(productUsecase usecase.ProductUsecase, validators serviceutils.CustomValidator, pageTokens *pagination.Codec) productpb.ProductServiceServer {
 	return &handler{
 		productUsecase: productUsecase,
 		validators:     validators,
+		pageTokens:     pageTokens,
 	}
 }
 
@@ -34,19 +44,7 @@ func (h *handler) GetProduct(ctx context.Context, req *productpb.GetProductReque
 		return nil, err
 	}
 
-	return &productpb.Product{
-		Id:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       int32(product.Price),
-		Seller: &userpb.User{
-			Id:      product.Seller.ID,
-			Email:   product.Seller.Email,
-			Name:    product.Seller.Name,
-			Address: product.Seller.Address,
-			Type:    string(product.Seller.Type),
-		},
-	}, nil
+	return toProductProto(product), nil
 }
 
 func (h *handler) GetProducts(ctx context.Context, filter *productpb.GetProductsRequest) (*productpb.GetProductsResponse, error) {
@@ -63,19 +61,7 @@ func (h *handler) GetProducts(ctx context.Context, filter *productpb.GetProducts
 
 		result := []*productpb.Product{}
 		for _, product := range products {
-			result = append(result, &productpb.Product{
-				Id:          product.ID,
-				Name:        product.Name,
-				Description: product.Description,
-				Price:       int32(product.Price),
-				Seller: &userpb.User{
-					Id:      product.Seller.ID,
-					Email:   product.Seller.Email,
-					Name:    product.Seller.Name,
-					Address: product.Seller.Address,
-					Type:    string(product.Seller.Type),
-				},
-			})
+			result = append(result, toProductProto(product))
 		}
 
 		return &productpb.GetProductsResponse{
@@ -83,43 +69,144 @@ func (h *handler) GetProducts(ctx context.Context, filter *productpb.GetProducts
 		}, nil
 	}
 
-	limit := filter.GetLimit()
-	if limit == 0 {
-		limit = 20
+	pageSize, err := pagination.PageSize(filter.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.MinPrice != nil && filter.MaxPrice != nil && filter.GetMinPrice() > filter.GetMaxPrice() {
+		return nil, status.Error(codes.InvalidArgument, "min_price must not be greater than max_price")
 	}
 
-	products, err := h.productUsecase.Fetch(ctx, models.ProductFilter{
-		PageSize: int(limit),
-		Page:     int(filter.GetOffset()),
-		Search:   filter.GetSearch(),
-	})
+	if filter.GetCurrencyCode() != "" && !money.ValidCurrencyCode(filter.GetCurrencyCode()) {
+		return nil, status.Error(codes.InvalidArgument, "invalid currency_code")
+	}
+
+	productSort, ok := productSorts[filter.GetSort()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid sort")
+	}
+
+	// Prices in different currencies cannot be compared.
+	pricedByCurrency := filter.MinPrice != nil || filter.MaxPrice != nil ||
+		productSort == models.ProductSortPriceAsc || productSort == models.ProductSortPriceDesc
+	if pricedByCurrency && filter.GetCurrencyCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "currency_code is required to filter or sort by price")
+	}
+	if filter.GetSort() == productpb.ProductSort_PRODUCT_SORT_UNSPECIFIED && filter.GetSearch() != "" {
+		productSort = models.ProductSortRelevance
+	}
+
+	// One extra product is fetched to tell whether there is a next page.
+	productFilter := models.ProductFilter{
+		PageSize:     pageSize + 1,
+		Search:       strings.TrimSpace(filter.GetSearch()),
+		SellerID:     filter.GetSellerId(),
+		MinPrice:     filter.MinPrice,
+		MaxPrice:     filter.MaxPrice,
+		CurrencyCode: filter.GetCurrencyCode(),
+		Sort:         productSort,
+	}
+
+	filterHash := pagination.FilterHash(
+		productFilter.Search,
+		productFilter.SellerID,
+		optionalInt(filter.MinPrice),
+		optionalInt(filter.MaxPrice),
+		productFilter.CurrencyCode,
+		strconv.Itoa(int(productFilter.Sort)),
+	)
+	if filter.GetPageToken() != "" {
+		cursor, err := h.pageTokens.Decode(filter.GetPageToken(), filterHash)
+		if err != nil {
+			return nil, err
+		}
+		productFilter.After = &cursor
+	}
+
+	products, err := h.productUsecase.Fetch(ctx, productFilter)
 	if err != nil {
 		log.Error().Err(err).Msg("failed get products")
 		return nil, err
 	}
 
+	var nextPageToken string
+	if len(products) > pageSize {
+		products = products[:pageSize]
+		last := products[pageSize-1]
+		cursor := pagination.Cursor{
+			CreatedAt: last.CreatedTime,
+			ID:        last.ID,
+			Filter:    filterHash,
+		}
+		switch productFilter.Sort {
+		case models.ProductSortRelevance:
+			cursor.Key = strconv.FormatFloat(float64(last.SearchRank), 'g', -1, 32)
+		case models.ProductSortPriceAsc, models.ProductSortPriceDesc:
+			cursor.Key = strconv.FormatInt(last.Price.MinorUnits, 10)
+		}
+
+		nextPageToken, err = h.pageTokens.Encode(cursor)
+		if err != nil {
+			log.Error().Err(err).Msg("failed encode page token")
+			return nil, status.Error(codes.Internal, "Internal Server Error")
+		}
+	}
+
+	facets, err := h.productUsecase.Facets(ctx, productFilter)
+	if err != nil {
+		log.Error().Err(err).Msg("failed get product facets")
+		return nil, err
+	}
+
 	result := []*productpb.Product{}
 	for _, product := range products {
-		result = append(result, &productpb.Product{
-			Id:          product.ID,
-			Name:        product.Name,
-			Description: product.Description,
-			Price:       int32(product.Price),
-			Seller: &userpb.User{
-				Id:      product.Seller.ID,
-				Email:   product.Seller.Email,
-				Name:    product.Seller.Name,
-				Address: product.Seller.Address,
-				Type:    string(product.Seller.Type),
-			},
-		})
+		result = append(result, toProductProto(product))
 	}
 
 	return &productpb.GetProductsResponse{
-		Result: result,
+		Result:        result,
+		NextPageToken: nextPageToken,
+		Facets:        toFacetsProto(facets),
 	}, nil
 }
 
+var productSorts = map[productpb.ProductSort]models.ProductSort{
+	productpb.ProductSort_PRODUCT_SORT_UNSPECIFIED: models.ProductSortNewest,
+	productpb.ProductSort_PRODUCT_SORT_RELEVANCE:   models.ProductSortRelevance,
+	productpb.ProductSort_PRODUCT_SORT_NEWEST:      models.ProductSortNewest,
+	productpb.ProductSort_PRODUCT_SORT_PRICE_ASC:   models.ProductSortPriceAsc,
+	productpb.ProductSort_PRODUCT_SORT_PRICE_DESC:  models.ProductSortPriceDesc,
+}
+
+func optionalInt(value *int64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatInt(*value, 10)
+}
+
+func toFacetsProto(facets models.ProductFacets) *productpb.ProductFacets {
+	result := &productpb.ProductFacets{}
+	for _, priceRange := range facets.PriceRanges {
+		facet := &productpb.PriceRangeFacet{
+			Min:   priceRange.Min,
+			Count: priceRange.Count,
+		}
+		if priceRange.Max != 0 {
+			facet.Max = &priceRange.Max
+		}
+		result.PriceRanges = append(result.PriceRanges, facet)
+	}
+	for _, seller := range facets.Sellers {
+		result.Sellers = append(result.Sellers, &productpb.SellerFacet{
+			SellerId: seller.SellerID,
+			Count:    seller.Count,
+		})
+	}
+	return result
+}
+
 func (h *handler) StoreProduct(ctx context.Context, request *productpb.StoreProductRequest) (*productpb.Product, error) {
 	log := zerolog.Ctx(ctx)
 	log.With().Str("func", "internal.service.product.StoreProduct").Logger()
@@ -128,7 +215,8 @@ func (h *handler) StoreProduct(ctx context.Context, request *productpb.StoreProd
 	productReq := models.Product{
 		Name:        request.GetName(),
 		Description: request.GetDescription(),
-		Price:       int64(request.Price),
+		Price:       money.FromProto(request.GetPrice()),
+		Stock:       request.GetStock(),
 	}
 
 	err := h.validators.Validate(productReq)
@@ -136,17 +224,173 @@ func (h *handler) StoreProduct(ctx context.Context, request *productpb.StoreProd
 		return nil, err
 	}
 
+	if err := validatePrice(productReq.Price); err != nil {
+		return nil, err
+	}
+
 	product, err := h.productUsecase.Store(ctx, productReq)
 	if err != nil {
 		log.Error().Err(err).Msg("failed store products")
 		return nil, err
 	}
 
-	productsGrpc := &productpb.Product{
+	return toProductProto(product), nil
+}
+
+func (h *handler) UpdateProduct(ctx context.Context, request *productpb.UpdateProductRequest) (*productpb.Product, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.product.UpdateProduct").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetProduct().GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	fields := request.GetUpdateMask().GetPaths()
+	if len(fields) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "update_mask is required")
+	}
+
+	for _, field := range fields {
+		switch field {
+		case "name":
+			if request.GetProduct().GetName() == "" {
+				return nil, status.Error(codes.InvalidArgument, "name required")
+			}
+		case "description":
+			if request.GetProduct().GetDescription() == "" {
+				return nil, status.Error(codes.InvalidArgument, "description required")
+			}
+		case "price":
+			if err := validatePrice(money.FromProto(request.GetProduct().GetPrice())); err != nil {
+				return nil, err
+			}
+		case "stock":
+			if request.GetProduct().GetStock() < 0 {
+				return nil, status.Error(codes.InvalidArgument, "stock min")
+			}
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "field %s cannot be updated", field)
+		}
+	}
+
+	product, err := h.productUsecase.Update(ctx, models.Product{
+		ID:          request.GetProduct().GetId(),
+		Name:        request.GetProduct().GetName(),
+		Description: request.GetProduct().GetDescription(),
+		Price:       money.FromProto(request.GetProduct().GetPrice()),
+		Stock:       request.GetProduct().GetStock(),
+	}, fields)
+	if err != nil {
+		log.Error().Err(err).Msg("failed update product")
+		return nil, err
+	}
+
+	return toProductProto(product), nil
+}
+
+func (h *handler) DeleteProduct(ctx context.Context, request *productpb.DeleteProductRequest) (*emptypb.Empty, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.product.DeleteProduct").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if err := h.productUsecase.Delete(ctx, request.GetId()); err != nil {
+		log.Error().Err(err).Msg("failed delete product")
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *handler) ReserveStock(ctx context.Context, request *productpb.ReserveStockRequest) (*productpb.StockReservation, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.product.ReserveStock").Logger()
+	log.Info().Msg("request received")
+
+	if len(request.GetItems()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "items is required")
+	}
+
+	items := []models.StockItem{}
+	for _, item := range request.GetItems() {
+		stockItem := models.StockItem{
+			ProductID: item.GetProductId(),
+			Quantity:  item.GetQuantity(),
+		}
+		if err := h.validators.Validate(stockItem); err != nil {
+			return nil, err
+		}
+		items = append(items, stockItem)
+	}
+
+	reservationID, err := h.productUsecase.ReserveStock(ctx, items)
+	if err != nil {
+		log.Error().Err(err).Msg("failed reserve stock")
+		return nil, err
+	}
+
+	return &productpb.StockReservation{
+		ReservationId: reservationID,
+	}, nil
+}
+
+func (h *handler) CommitStock(ctx context.Context, request *productpb.StockReservation) (*emptypb.Empty, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.product.CommitStock").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetReservationId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "reservation_id is required")
+	}
+
+	if err := h.productUsecase.CommitStock(ctx, request.GetReservationId()); err != nil {
+		log.Error().Err(err).Msg("failed commit stock")
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (h *handler) ReleaseStock(ctx context.Context, request *productpb.StockReservation) (*emptypb.Empty, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.product.ReleaseStock").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetReservationId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "reservation_id is required")
+	}
+
+	if err := h.productUsecase.ReleaseStock(ctx, request.GetReservationId()); err != nil {
+		log.Error().Err(err).Msg("failed release stock")
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func validatePrice(price money.Money) error {
+	if price.MinorUnits <= 0 {
+		return status.Error(codes.InvalidArgument, "price required")
+	}
+
+	if !money.ValidCurrencyCode(price.CurrencyCode) {
+		return status.Error(codes.InvalidArgument, "price currency_code must be an ISO 4217 code")
+	}
+
+	return nil
+}
+
+func toProductProto(product models.Product) *productpb.Product {
+	return &productpb.Product{
 		Id:          product.ID,
 		Name:        product.Name,
 		Description: product.Description,
-		Price:       int32(product.Price),
+		Price:       product.Price.Proto(),
+		Stock:       product.Stock,
 		Seller: &userpb.User{
 			Id:      product.Seller.ID,
 			Email:   product.Seller.Email,
@@ -155,6 +399,4 @@ func (h *handler) StoreProduct(ctx context.Context, request *productpb.StoreProd
 			Type:    string(product.Seller.Type),
 		},
 	}
-
-	return productsGrpc, nil
 }
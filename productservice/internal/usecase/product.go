@@ -17,7 +17,13 @@ type ProductUsecase interface {
 	Store(ctx context.Context, product models.Product) (models.Product, error)
 	Get(ctx context.Context, ID string) (models.Product, error)
 	Fetch(ctx context.Context, filter models.ProductFilter) ([]models.Product, error)
+	Facets(ctx context.Context, filter models.ProductFilter) (models.ProductFacets, error)
 	FetchByIds(ctx context.Context, ids []string) (map[string]models.Product, error)
+	Update(ctx context.Context, product models.Product, fields []string) (models.Product, error)
+	Delete(ctx context.Context, ID string) error
+	ReserveStock(ctx context.Context, items []models.StockItem) (string, error)
+	CommitStock(ctx context.Context, reservationID string) error
+	ReleaseStock(ctx context.Context, reservationID string) error
 }
 
 type usecase struct {
@@ -42,8 +48,8 @@ func (u *usecase) Store(ctx context.Context, product models.Product) (models.Pro
 		return models.Product{}, err
 	}
 
-	if user.Type != auth.UserSellerType {
-		return models.Product{}, status.Error(codes.Unauthenticated, "invalid user")
+	if !user.EmailVerified {
+		return models.Product{}, status.Error(codes.FailedPrecondition, "verify your email before publishing products")
 	}
 
 	product.Seller.ID = user.ID
@@ -80,14 +86,7 @@ func (u *usecase) Fetch(ctx context.Context, filter models.ProductFilter) ([]mod
 	log := zerolog.Ctx(ctx)
 	log.With().Str("func", "internal.usecase.product.Fetch").Logger()
 
-	user, err := auth.GetUserClaims(ctx)
-	if err == nil {
-		if user.Type == auth.UserSellerType {
-			filter.SellerID = user.ID
-		}
-	}
-
-	result, err := u.productRepo.Fetch(ctx, filter)
+	result, err := u.productRepo.Fetch(ctx, scopeFilter(ctx, filter))
 	if err != nil {
 		log.Error().Err(err).Msg("failed fetch product")
 		return make([]models.Product, 0), errors.Wrap(err, "product.service.fetch: fetch from repository")
@@ -111,6 +110,28 @@ func (u *usecase) Fetch(ctx context.Context, filter models.ProductFilter) ([]mod
 	return result, nil
 }
 
+func (u *usecase) Facets(ctx context.Context, filter models.ProductFilter) (models.ProductFacets, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.product.Facets").Logger()
+
+	result, err := u.productRepo.Facets(ctx, scopeFilter(ctx, filter))
+	if err != nil {
+		log.Error().Err(err).Msg("failed fetch product facets")
+		return models.ProductFacets{}, errors.Wrap(err, "product.service.facets: facets from repository")
+	}
+
+	return result, nil
+}
+
+// scopeFilter limits sellers to their own products.
+func scopeFilter(ctx context.Context, filter models.ProductFilter) models.ProductFilter {
+	user, err := auth.GetUserClaims(ctx)
+	if err == nil && user.Type == auth.UserSellerType {
+		filter.SellerID = user.ID
+	}
+	return filter
+}
+
 func (u *usecase) FetchByIds(ctx context.Context, ids []string) (map[string]models.Product, error) {
 	log := zerolog.Ctx(ctx)
 	log.With().Str("func", "internal.usecase.product.FetchByIds").Logger()
@@ -139,3 +160,135 @@ func (u *usecase) FetchByIds(ctx context.Context, ids []string) (map[string]mode
 
 	return result, nil
 }
+
+func (u *usecase) Update(ctx context.Context, product models.Product, fields []string) (models.Product, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.product.Update").Logger()
+
+	current, err := u.getOwnedProduct(ctx, product.ID)
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	for _, field := range fields {
+		switch field {
+		case "name":
+			current.Name = product.Name
+		case "description":
+			current.Description = product.Description
+		case "price":
+			current.Price = product.Price
+		case "stock":
+			current.Stock = product.Stock
+		}
+	}
+
+	result, err := u.productRepo.Update(ctx, current, fields)
+	if err != nil {
+		log.Error().Err(err).Msg("failed update product")
+		return models.Product{}, errors.Wrap(err, "product.service.update: update from repository")
+	}
+
+	users, err := u.usrClient.FetchByIDs(ctx, []string{result.Seller.ID})
+	if err != nil {
+		log.Error().Err(err).Msg("failed fetch user by ids")
+		return models.Product{}, errors.Wrap(err, "product.service.update: get user from userservice grpc")
+	}
+
+	result.Seller = users[result.Seller.ID]
+	return result, nil
+}
+
+func (u *usecase) Delete(ctx context.Context, ID string) error {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.product.Delete").Logger()
+
+	if _, err := u.getOwnedProduct(ctx, ID); err != nil {
+		return err
+	}
+
+	err := u.productRepo.Delete(ctx, ID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed delete product")
+		return errors.Wrap(err, "product.service.delete: delete from repository")
+	}
+
+	return nil
+}
+
+func (u *usecase) ReserveStock(ctx context.Context, items []models.StockItem) (string, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.product.ReserveStock").Logger()
+
+	// Merge duplicate lines so each product row is only locked once.
+	quantities := map[string]int64{}
+	merged := []models.StockItem{}
+	for _, item := range items {
+		if _, ok := quantities[item.ProductID]; !ok {
+			merged = append(merged, models.StockItem{ProductID: item.ProductID})
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+	for index := range merged {
+		merged[index].Quantity = quantities[merged[index].ProductID]
+	}
+
+	reservationID, err := u.productRepo.ReserveStock(ctx, merged)
+	if err != nil {
+		log.Error().Err(err).Msg("failed reserve stock")
+		return "", errors.Wrap(err, "product.service.reserveStock: reserve from repository")
+	}
+
+	return reservationID, nil
+}
+
+func (u *usecase) CommitStock(ctx context.Context, reservationID string) error {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.product.CommitStock").Logger()
+
+	err := u.productRepo.CommitStock(ctx, reservationID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed commit stock")
+		return errors.Wrap(err, "product.service.commitStock: commit from repository")
+	}
+
+	return nil
+}
+
+func (u *usecase) ReleaseStock(ctx context.Context, reservationID string) error {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.product.ReleaseStock").Logger()
+
+	err := u.productRepo.ReleaseStock(ctx, reservationID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed release stock")
+		return errors.Wrap(err, "product.service.releaseStock: release from repository")
+	}
+
+	return nil
+}
+
+// getOwnedProduct loads a product on behalf of the calling seller. Products owned by
+// another seller are reported as not found so their existence is not leaked. Admins
+// may load any product.
+func (u *usecase) getOwnedProduct(ctx context.Context, ID string) (models.Product, error) {
+	log := zerolog.Ctx(ctx)
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed GetUserClaims")
+		return models.Product{}, err
+	}
+
+	product, err := u.productRepo.Get(ctx, ID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed get product")
+		return models.Product{}, errors.Wrap(err, "product.service.get: get from repository")
+	}
+
+	if user.Type != auth.UserAdminType && product.Seller.ID != user.ID {
+		return models.Product{}, status.Error(codes.NotFound, "product not found")
+	}
+
+	return product, nil
+}
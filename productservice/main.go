@@ -32,6 +32,9 @@ import (
 	userpb "github.com/situmorangbastian/skyros/proto/user"
 	"github.com/situmorangbastian/skyros/serviceutils"
 	"github.com/situmorangbastian/skyros/serviceutils/auth"
+	"github.com/situmorangbastian/skyros/serviceutils/idempotency"
+	"github.com/situmorangbastian/skyros/serviceutils/outbox"
+	"github.com/situmorangbastian/skyros/serviceutils/pagination"
 )
 
 func main() {
@@ -64,7 +67,9 @@ func main() {
 
 	required := []string{
 		"DATABASE_URL",
-		"SECRET_KEY",
+		"SERVICE_KEY_FILE",
+		"SERVICE_TRUSTED_KEYS_DIR",
+		"PAGE_TOKEN_SECRET",
 		"GRPC_SERVER_PORT",
 		"GRPC_SERVICE_ENDPOINT",
 		"USER_SERVICE_GRPC",
@@ -94,10 +99,29 @@ func main() {
 	}
 	log.Info().Msg("migrations applied successfully")
 
+	serviceKey, err := auth.ReadPrivateKey(cfg.GetString("SERVICE_KEY_FILE"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load service key")
+	}
+	serviceTokens := auth.NewServiceTokenSource("productservice", serviceKey)
+
+	trustedServiceKeys, err := auth.ReadPublicKeys(cfg.GetString("SERVICE_TRUSTED_KEYS_DIR"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load trusted service keys")
+	}
+
+	identities, err := auth.NewIdentityVerifier(trustedServiceKeys, "gatewayservice")
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load identity issuers")
+	}
+
 	userConn, err := grpc.NewClient(
 		cfg.GetString("USER_SERVICE_GRPC"),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithUnaryInterceptor(serviceutils.CorrelationClientInterceptor()),
+		grpc.WithChainUnaryInterceptor(
+			serviceutils.CorrelationClientInterceptor(),
+			auth.ServiceClientInterceptor(serviceTokens),
+		),
 	)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to connect user service client")
@@ -112,11 +136,22 @@ func main() {
 		grpc.ChainUnaryInterceptor(
 			serviceutils.CorrelationServerInterceptorWithLogging(),
 			serviceutils.TraceErrors(),
-			auth.AuthInterceptor(cfg.GetString("SECRET_KEY"), userClient),
+			auth.ServiceAuthInterceptor(trustedServiceKeys, auth.ServiceACL{
+				productpb.ProductService_ReserveStock_FullMethodName: {"orderservice"},
+				productpb.ProductService_CommitStock_FullMethodName:  {"orderservice"},
+				productpb.ProductService_ReleaseStock_FullMethodName: {"orderservice"},
+			}),
+			auth.AuthInterceptor(auth.NewKeySet(userSvcClient), userClient, identities),
+			auth.PolicyInterceptor(auth.Policy{
+				productpb.ProductService_StoreProduct_FullMethodName:  {auth.UserSellerType},
+				productpb.ProductService_UpdateProduct_FullMethodName: {auth.UserSellerType, auth.UserAdminType},
+				productpb.ProductService_DeleteProduct_FullMethodName: {auth.UserSellerType, auth.UserAdminType},
+			}),
+			idempotency.Interceptor(idempotency.NewPostgresStore(dbpool), productpb.ProductService_StoreProduct_FullMethodName),
 		),
 	)
 
-	productService := service.NewProductService(productUsecase, serviceutils.NewCustomValidator())
+	productService := service.NewProductService(productUsecase, serviceutils.NewCustomValidator(), pagination.NewCodec(cfg.GetString("PAGE_TOKEN_SECRET")))
 	productpb.RegisterProductServiceServer(grpcServer, productService)
 
 	mux := runtime.NewServeMux(
@@ -129,6 +164,7 @@ func main() {
 			},
 		}),
 		runtime.WithErrorHandler(serviceutils.NewRestErrorHandler()),
+		runtime.WithIncomingHeaderMatcher(idempotency.HeaderMatcher),
 	)
 
 	if err := productpb.RegisterProductServiceHandlerFromEndpoint(
@@ -147,6 +183,14 @@ func main() {
 
 	var wg sync.WaitGroup
 
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		log.Info().Msg("outbox relay starting")
+		outbox.NewRelay(dbpool, outbox.NewPostgresPublisher(dbpool)).Run(relayCtx)
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -190,6 +234,7 @@ func main() {
 	}
 
 	grpcServer.GracefulStop()
+	stopRelay()
 
 	if err := userConn.Close(); err != nil {
 		log.Error().Err(err).Msg("failed to close user service gRPC connection")
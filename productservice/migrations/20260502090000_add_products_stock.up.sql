@@ -0,0 +1,15 @@
+-- Existing products start without stock and cannot be ordered until it is seeded with
+-- `make seed-stock STOCK=<units>` or set by their sellers.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS stock BIGINT NOT NULL DEFAULT 0 CHECK (stock >= 0);
+
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id UUID PRIMARY KEY,
+    reservation_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    quantity BIGINT NOT NULL,
+    status SMALLINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS stock_reservations_reservation_id_idx ON stock_reservations (reservation_id);
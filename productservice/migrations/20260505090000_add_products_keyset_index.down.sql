@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS products_created_at_id_idx;
+
+ALTER TABLE products ALTER COLUMN created_at DROP NOT NULL;
@@ -0,0 +1,4 @@
+UPDATE products SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE products ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS products_created_at_id_idx ON products (created_at DESC, id DESC) WHERE deleted_at IS NULL;
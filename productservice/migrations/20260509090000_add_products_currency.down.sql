@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS products_currency_price_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,5 @@
+-- Prices stored before currencies existed are taken to be IDR minor units.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE products ALTER COLUMN currency DROP DEFAULT;
+
+CREATE INDEX IF NOT EXISTS products_currency_price_idx ON products (currency, price, id) WHERE deleted_at IS NULL;
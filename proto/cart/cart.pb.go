@@ -0,0 +1,620 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: cart/cart.proto
+
+package cart
+
+import (
+	common "github.com/situmorangbastian/skyros/proto/common"
+	order "github.com/situmorangbastian/skyros/proto/order"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CartItemWarning int32
+
+const (
+	CartItemWarning_CART_ITEM_WARNING_UNSPECIFIED CartItemWarning = 0
+	// The product's price differs from added_unit_price.
+	CartItemWarning_CART_ITEM_WARNING_PRICE_CHANGED CartItemWarning = 1
+	// The product was deleted or cannot be priced right now.
+	CartItemWarning_CART_ITEM_WARNING_PRODUCT_UNAVAILABLE CartItemWarning = 2
+)
+
+// Enum value maps for CartItemWarning.
+var (
+	CartItemWarning_name = map[int32]string{
+		0: "CART_ITEM_WARNING_UNSPECIFIED",
+		1: "CART_ITEM_WARNING_PRICE_CHANGED",
+		2: "CART_ITEM_WARNING_PRODUCT_UNAVAILABLE",
+	}
+	CartItemWarning_value = map[string]int32{
+		"CART_ITEM_WARNING_UNSPECIFIED":         0,
+		"CART_ITEM_WARNING_PRICE_CHANGED":       1,
+		"CART_ITEM_WARNING_PRODUCT_UNAVAILABLE": 2,
+	}
+)
+
+func (x CartItemWarning) Enum() *CartItemWarning {
+	p := new(CartItemWarning)
+	*p = x
+	return p
+}
+
+func (x CartItemWarning) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CartItemWarning) Descriptor() protoreflect.EnumDescriptor {
+	return file_cart_cart_proto_enumTypes[0].Descriptor()
+}
+
+func (CartItemWarning) Type() protoreflect.EnumType {
+	return &file_cart_cart_proto_enumTypes[0]
+}
+
+func (x CartItemWarning) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CartItemWarning.Descriptor instead.
+func (CartItemWarning) EnumDescriptor() ([]byte, []int) {
+	return file_cart_cart_proto_rawDescGZIP(), []int{0}
+}
+
+type CartItem struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity  int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Name      string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	SellerId  string                 `protobuf:"bytes,4,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	// Current price of the product, unset when it is unavailable.
+	UnitPrice *common.Money `protobuf:"bytes,5,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	// Price of the product when the item was last added or updated.
+	AddedUnitPrice *common.Money     `protobuf:"bytes,6,opt,name=added_unit_price,json=addedUnitPrice,proto3" json:"added_unit_price,omitempty"`
+	Subtotal       *common.Money     `protobuf:"bytes,7,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	Warnings       []CartItemWarning `protobuf:"varint,8,rep,packed,name=warnings,proto3,enum=cart.CartItemWarning" json:"warnings,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CartItem) Reset() {
+	*x = CartItem{}
+	mi := &file_cart_cart_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartItem) ProtoMessage() {}
+
+func (x *CartItem) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_cart_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartItem.ProtoReflect.Descriptor instead.
+func (*CartItem) Descriptor() ([]byte, []int) {
+	return file_cart_cart_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CartItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CartItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CartItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CartItem) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *CartItem) GetUnitPrice() *common.Money {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return nil
+}
+
+func (x *CartItem) GetAddedUnitPrice() *common.Money {
+	if x != nil {
+		return x.AddedUnitPrice
+	}
+	return nil
+}
+
+func (x *CartItem) GetSubtotal() *common.Money {
+	if x != nil {
+		return x.Subtotal
+	}
+	return nil
+}
+
+func (x *CartItem) GetWarnings() []CartItemWarning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Cart holds the buyer's items between sessions, priced at the products' current
+// prices.
+type Cart struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Items []*CartItem            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// Sum of the available items.
+	Total *common.Money `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"`
+	// Unset for an empty cart. Every change to the cart extends it.
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cart) Reset() {
+	*x = Cart{}
+	mi := &file_cart_cart_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cart) ProtoMessage() {}
+
+func (x *Cart) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_cart_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cart.ProtoReflect.Descriptor instead.
+func (*Cart) Descriptor() ([]byte, []int) {
+	return file_cart_cart_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Cart) GetItems() []*CartItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Cart) GetTotal() *common.Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *Cart) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type GetCartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCartRequest) Reset() {
+	*x = GetCartRequest{}
+	mi := &file_cart_cart_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCartRequest) ProtoMessage() {}
+
+func (x *GetCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_cart_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCartRequest.ProtoReflect.Descriptor instead.
+func (*GetCartRequest) Descriptor() ([]byte, []int) {
+	return file_cart_cart_proto_rawDescGZIP(), []int{2}
+}
+
+type AddCartItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCartItemRequest) Reset() {
+	*x = AddCartItemRequest{}
+	mi := &file_cart_cart_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCartItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCartItemRequest) ProtoMessage() {}
+
+func (x *AddCartItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_cart_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCartItemRequest.ProtoReflect.Descriptor instead.
+func (*AddCartItemRequest) Descriptor() ([]byte, []int) {
+	return file_cart_cart_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AddCartItemRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *AddCartItemRequest) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type UpdateCartItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCartItemRequest) Reset() {
+	*x = UpdateCartItemRequest{}
+	mi := &file_cart_cart_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCartItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCartItemRequest) ProtoMessage() {}
+
+func (x *UpdateCartItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_cart_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCartItemRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCartItemRequest) Descriptor() ([]byte, []int) {
+	return file_cart_cart_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateCartItemRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *UpdateCartItemRequest) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type RemoveCartItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveCartItemRequest) Reset() {
+	*x = RemoveCartItemRequest{}
+	mi := &file_cart_cart_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveCartItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveCartItemRequest) ProtoMessage() {}
+
+func (x *RemoveCartItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_cart_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveCartItemRequest.ProtoReflect.Descriptor instead.
+func (*RemoveCartItemRequest) Descriptor() ([]byte, []int) {
+	return file_cart_cart_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RemoveCartItemRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type CheckoutRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Description string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	// ID of the buyer's address book entry the orders are delivered to.
+	AddressId     string `protobuf:"bytes,4,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	CouponCode    string `protobuf:"bytes,3,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckoutRequest) Reset() {
+	*x = CheckoutRequest{}
+	mi := &file_cart_cart_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutRequest) ProtoMessage() {}
+
+func (x *CheckoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_cart_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutRequest.ProtoReflect.Descriptor instead.
+func (*CheckoutRequest) Descriptor() ([]byte, []int) {
+	return file_cart_cart_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CheckoutRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CheckoutRequest) GetAddressId() string {
+	if x != nil {
+		return x.AddressId
+	}
+	return ""
+}
+
+func (x *CheckoutRequest) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+var File_cart_cart_proto protoreflect.FileDescriptor
+
+const file_cart_cart_proto_rawDesc = "" +
+	"\n" +
+	"\x0fcart/cart.proto\x12\x04cart\x1a\x12common/types.proto\x1a\x11order/order.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbb\x02\n" +
+	"\bCartItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1b\n" +
+	"\tseller_id\x18\x04 \x01(\tR\bsellerId\x12,\n" +
+	"\n" +
+	"unit_price\x18\x05 \x01(\v2\r.common.MoneyR\tunitPrice\x127\n" +
+	"\x10added_unit_price\x18\x06 \x01(\v2\r.common.MoneyR\x0eaddedUnitPrice\x12)\n" +
+	"\bsubtotal\x18\a \x01(\v2\r.common.MoneyR\bsubtotal\x121\n" +
+	"\bwarnings\x18\b \x03(\x0e2\x15.cart.CartItemWarningR\bwarnings\"\x8c\x01\n" +
+	"\x04Cart\x12$\n" +
+	"\x05items\x18\x01 \x03(\v2\x0e.cart.CartItemR\x05items\x12#\n" +
+	"\x05total\x18\x02 \x01(\v2\r.common.MoneyR\x05total\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\x10\n" +
+	"\x0eGetCartRequest\"O\n" +
+	"\x12AddCartItemRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\"R\n" +
+	"\x15UpdateCartItemRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\"6\n" +
+	"\x15RemoveCartItemRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"\x8e\x01\n" +
+	"\x0fCheckoutRequest\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"address_id\x18\x04 \x01(\tR\taddressId\x12\x1f\n" +
+	"\vcoupon_code\x18\x03 \x01(\tR\n" +
+	"couponCodeJ\x04\b\x02\x10\x03R\x13destination_address*\x84\x01\n" +
+	"\x0fCartItemWarning\x12!\n" +
+	"\x1dCART_ITEM_WARNING_UNSPECIFIED\x10\x00\x12#\n" +
+	"\x1fCART_ITEM_WARNING_PRICE_CHANGED\x10\x01\x12)\n" +
+	"%CART_ITEM_WARNING_PRODUCT_UNAVAILABLE\x10\x022\xbc\x03\n" +
+	"\vCartService\x12=\n" +
+	"\aGetCart\x12\x14.cart.GetCartRequest\x1a\n" +
+	".cart.Cart\"\x10\x82\xd3\xe4\x93\x02\n" +
+	"\x12\b/v1/cart\x12N\n" +
+	"\vAddCartItem\x12\x18.cart.AddCartItemRequest\x1a\n" +
+	".cart.Cart\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/cart/items\x12a\n" +
+	"\x0eUpdateCartItem\x12\x1b.cart.UpdateCartItemRequest\x1a\n" +
+	".cart.Cart\"&\x82\xd3\xe4\x93\x02 :\x01*2\x1b/v1/cart/items/{product_id}\x12^\n" +
+	"\x0eRemoveCartItem\x12\x1b.cart.RemoveCartItemRequest\x1a\n" +
+	".cart.Cart\"#\x82\xd3\xe4\x93\x02\x1d*\x1b/v1/cart/items/{product_id}\x12[\n" +
+	"\bCheckout\x12\x15.cart.CheckoutRequest\x1a\x1a.order.CreateOrderResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/cart/checkoutB5Z3github.com/situmorangbastian/skyros/proto/cart;cartb\x06proto3"
+
+var (
+	file_cart_cart_proto_rawDescOnce sync.Once
+	file_cart_cart_proto_rawDescData []byte
+)
+
+func file_cart_cart_proto_rawDescGZIP() []byte {
+	file_cart_cart_proto_rawDescOnce.Do(func() {
+		file_cart_cart_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_cart_cart_proto_rawDesc), len(file_cart_cart_proto_rawDesc)))
+	})
+	return file_cart_cart_proto_rawDescData
+}
+
+var file_cart_cart_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_cart_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_cart_cart_proto_goTypes = []any{
+	(CartItemWarning)(0),              // 0: cart.CartItemWarning
+	(*CartItem)(nil),                  // 1: cart.CartItem
+	(*Cart)(nil),                      // 2: cart.Cart
+	(*GetCartRequest)(nil),            // 3: cart.GetCartRequest
+	(*AddCartItemRequest)(nil),        // 4: cart.AddCartItemRequest
+	(*UpdateCartItemRequest)(nil),     // 5: cart.UpdateCartItemRequest
+	(*RemoveCartItemRequest)(nil),     // 6: cart.RemoveCartItemRequest
+	(*CheckoutRequest)(nil),           // 7: cart.CheckoutRequest
+	(*common.Money)(nil),              // 8: common.Money
+	(*timestamppb.Timestamp)(nil),     // 9: google.protobuf.Timestamp
+	(*order.CreateOrderResponse)(nil), // 10: order.CreateOrderResponse
+}
+var file_cart_cart_proto_depIdxs = []int32{
+	8,  // 0: cart.CartItem.unit_price:type_name -> common.Money
+	8,  // 1: cart.CartItem.added_unit_price:type_name -> common.Money
+	8,  // 2: cart.CartItem.subtotal:type_name -> common.Money
+	0,  // 3: cart.CartItem.warnings:type_name -> cart.CartItemWarning
+	1,  // 4: cart.Cart.items:type_name -> cart.CartItem
+	8,  // 5: cart.Cart.total:type_name -> common.Money
+	9,  // 6: cart.Cart.expires_at:type_name -> google.protobuf.Timestamp
+	3,  // 7: cart.CartService.GetCart:input_type -> cart.GetCartRequest
+	4,  // 8: cart.CartService.AddCartItem:input_type -> cart.AddCartItemRequest
+	5,  // 9: cart.CartService.UpdateCartItem:input_type -> cart.UpdateCartItemRequest
+	6,  // 10: cart.CartService.RemoveCartItem:input_type -> cart.RemoveCartItemRequest
+	7,  // 11: cart.CartService.Checkout:input_type -> cart.CheckoutRequest
+	2,  // 12: cart.CartService.GetCart:output_type -> cart.Cart
+	2,  // 13: cart.CartService.AddCartItem:output_type -> cart.Cart
+	2,  // 14: cart.CartService.UpdateCartItem:output_type -> cart.Cart
+	2,  // 15: cart.CartService.RemoveCartItem:output_type -> cart.Cart
+	10, // 16: cart.CartService.Checkout:output_type -> order.CreateOrderResponse
+	12, // [12:17] is the sub-list for method output_type
+	7,  // [7:12] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_cart_cart_proto_init() }
+func file_cart_cart_proto_init() {
+	if File_cart_cart_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cart_cart_proto_rawDesc), len(file_cart_cart_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_cart_cart_proto_goTypes,
+		DependencyIndexes: file_cart_cart_proto_depIdxs,
+		EnumInfos:         file_cart_cart_proto_enumTypes,
+		MessageInfos:      file_cart_cart_proto_msgTypes,
+	}.Build()
+	File_cart_cart_proto = out.File
+	file_cart_cart_proto_goTypes = nil
+	file_cart_cart_proto_depIdxs = nil
+}
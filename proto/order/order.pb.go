@@ -7,11 +7,13 @@
 package order
 
 import (
-	_ "github.com/situmorangbastian/skyros/proto/common"
+	common "github.com/situmorangbastian/skyros/proto/common"
+	payment "github.com/situmorangbastian/skyros/proto/payment"
 	user "github.com/situmorangbastian/skyros/proto/user"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -24,10 +26,136 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type OrderStatus int32
+
+const (
+	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0
+	OrderStatus_ORDER_STATUS_PENDING     OrderStatus = 1
+	OrderStatus_ORDER_STATUS_ACCEPTED    OrderStatus = 2
+	OrderStatus_ORDER_STATUS_SHIPPED     OrderStatus = 3
+	OrderStatus_ORDER_STATUS_DELIVERED   OrderStatus = 4
+	OrderStatus_ORDER_STATUS_CANCELLED   OrderStatus = 5
+	OrderStatus_ORDER_STATUS_REJECTED    OrderStatus = 6
+	// Paid and waiting for the seller to accept. Set when the order's payment succeeds.
+	OrderStatus_ORDER_STATUS_PAID OrderStatus = 7
+)
+
+// Enum value maps for OrderStatus.
+var (
+	OrderStatus_name = map[int32]string{
+		0: "ORDER_STATUS_UNSPECIFIED",
+		1: "ORDER_STATUS_PENDING",
+		2: "ORDER_STATUS_ACCEPTED",
+		3: "ORDER_STATUS_SHIPPED",
+		4: "ORDER_STATUS_DELIVERED",
+		5: "ORDER_STATUS_CANCELLED",
+		6: "ORDER_STATUS_REJECTED",
+		7: "ORDER_STATUS_PAID",
+	}
+	OrderStatus_value = map[string]int32{
+		"ORDER_STATUS_UNSPECIFIED": 0,
+		"ORDER_STATUS_PENDING":     1,
+		"ORDER_STATUS_ACCEPTED":    2,
+		"ORDER_STATUS_SHIPPED":     3,
+		"ORDER_STATUS_DELIVERED":   4,
+		"ORDER_STATUS_CANCELLED":   5,
+		"ORDER_STATUS_REJECTED":    6,
+		"ORDER_STATUS_PAID":        7,
+	}
+)
+
+func (x OrderStatus) Enum() *OrderStatus {
+	p := new(OrderStatus)
+	*p = x
+	return p
+}
+
+func (x OrderStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_order_order_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderStatus) Type() protoreflect.EnumType {
+	return &file_order_order_proto_enumTypes[0]
+}
+
+func (x OrderStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderStatus.Descriptor instead.
+func (OrderStatus) EnumDescriptor() ([]byte, []int) {
+	return file_order_order_proto_rawDescGZIP(), []int{0}
+}
+
+type OrderSort int32
+
+const (
+	// Newest first.
+	OrderSort_ORDER_SORT_UNSPECIFIED      OrderSort = 0
+	OrderSort_ORDER_SORT_NEWEST           OrderSort = 1
+	OrderSort_ORDER_SORT_OLDEST           OrderSort = 2
+	OrderSort_ORDER_SORT_TOTAL_PRICE_ASC  OrderSort = 3
+	OrderSort_ORDER_SORT_TOTAL_PRICE_DESC OrderSort = 4
+)
+
+// Enum value maps for OrderSort.
+var (
+	OrderSort_name = map[int32]string{
+		0: "ORDER_SORT_UNSPECIFIED",
+		1: "ORDER_SORT_NEWEST",
+		2: "ORDER_SORT_OLDEST",
+		3: "ORDER_SORT_TOTAL_PRICE_ASC",
+		4: "ORDER_SORT_TOTAL_PRICE_DESC",
+	}
+	OrderSort_value = map[string]int32{
+		"ORDER_SORT_UNSPECIFIED":      0,
+		"ORDER_SORT_NEWEST":           1,
+		"ORDER_SORT_OLDEST":           2,
+		"ORDER_SORT_TOTAL_PRICE_ASC":  3,
+		"ORDER_SORT_TOTAL_PRICE_DESC": 4,
+	}
+)
+
+func (x OrderSort) Enum() *OrderSort {
+	p := new(OrderSort)
+	*p = x
+	return p
+}
+
+func (x OrderSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_order_order_proto_enumTypes[1].Descriptor()
+}
+
+func (OrderSort) Type() protoreflect.EnumType {
+	return &file_order_order_proto_enumTypes[1]
+}
+
+func (x OrderSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderSort.Descriptor instead.
+func (OrderSort) EnumDescriptor() ([]byte, []int) {
+	return file_order_order_proto_rawDescGZIP(), []int{1}
+}
+
 type OrderProduct struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity  int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Snapshot of the product when the order was placed. Ignored on CreateOrder.
+	Name          string        `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	UnitPrice     *common.Money `protobuf:"bytes,7,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	SellerId      string        `protobuf:"bytes,5,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	SellerName    string        `protobuf:"bytes,6,opt,name=seller_name,json=sellerName,proto3" json:"seller_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -76,21 +204,54 @@ func (x *OrderProduct) GetQuantity() int64 {
 	return 0
 }
 
+func (x *OrderProduct) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OrderProduct) GetUnitPrice() *common.Money {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return nil
+}
+
+func (x *OrderProduct) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *OrderProduct) GetSellerName() string {
+	if x != nil {
+		return x.SellerName
+	}
+	return ""
+}
+
 type Order struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
 	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Description        string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
 	SourceAddress      string                 `protobuf:"bytes,3,opt,name=source_address,json=sourceAddress,proto3" json:"source_address,omitempty"`
 	DestinationAddress string                 `protobuf:"bytes,4,opt,name=destination_address,json=destinationAddress,proto3" json:"destination_address,omitempty"`
-	TotalPrice         int64                  `protobuf:"varint,5,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	TotalPrice         *common.Money          `protobuf:"bytes,13,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
 	Seller             *user.User             `protobuf:"bytes,6,opt,name=seller,proto3" json:"seller,omitempty"`
 	Buyer              *user.User             `protobuf:"bytes,7,opt,name=buyer,proto3" json:"buyer,omitempty"`
-	Status             string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
 	Items              []*OrderProduct        `protobuf:"bytes,9,rep,name=items,proto3" json:"items,omitempty"`
 	CreatedAt          string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt          string                 `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	CheckoutId         string                 `protobuf:"bytes,12,opt,name=checkout_id,json=checkoutId,proto3" json:"checkout_id,omitempty"`
+	// Sum of the items before discounts; total_price is what the buyer pays.
+	Subtotal      *common.Money         `protobuf:"bytes,14,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	Discounts     []*OrderDiscount      `protobuf:"bytes,15,rep,name=discounts,proto3" json:"discounts,omitempty"`
+	PaymentStatus payment.PaymentStatus `protobuf:"varint,16,opt,name=payment_status,json=paymentStatus,proto3,enum=payment.PaymentStatus" json:"payment_status,omitempty"`
+	OrderStatus   OrderStatus           `protobuf:"varint,17,opt,name=order_status,json=orderStatus,proto3,enum=order.OrderStatus" json:"order_status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Order) Reset() {
@@ -151,11 +312,11 @@ func (x *Order) GetDestinationAddress() string {
 	return ""
 }
 
-func (x *Order) GetTotalPrice() int64 {
+func (x *Order) GetTotalPrice() *common.Money {
 	if x != nil {
 		return x.TotalPrice
 	}
-	return 0
+	return nil
 }
 
 func (x *Order) GetSeller() *user.User {
@@ -172,13 +333,6 @@ func (x *Order) GetBuyer() *user.User {
 	return nil
 }
 
-func (x *Order) GetStatus() string {
-	if x != nil {
-		return x.Status
-	}
-	return ""
-}
-
 func (x *Order) GetItems() []*OrderProduct {
 	if x != nil {
 		return x.Items
@@ -200,18 +354,116 @@ func (x *Order) GetUpdatedAt() string {
 	return ""
 }
 
+func (x *Order) GetCheckoutId() string {
+	if x != nil {
+		return x.CheckoutId
+	}
+	return ""
+}
+
+func (x *Order) GetSubtotal() *common.Money {
+	if x != nil {
+		return x.Subtotal
+	}
+	return nil
+}
+
+func (x *Order) GetDiscounts() []*OrderDiscount {
+	if x != nil {
+		return x.Discounts
+	}
+	return nil
+}
+
+func (x *Order) GetPaymentStatus() payment.PaymentStatus {
+	if x != nil {
+		return x.PaymentStatus
+	}
+	return payment.PaymentStatus(0)
+}
+
+func (x *Order) GetOrderStatus() OrderStatus {
+	if x != nil {
+		return x.OrderStatus
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+type OrderDiscount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CouponId      string                 `protobuf:"bytes,1,opt,name=coupon_id,json=couponId,proto3" json:"coupon_id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Amount        *common.Money          `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderDiscount) Reset() {
+	*x = OrderDiscount{}
+	mi := &file_order_order_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderDiscount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderDiscount) ProtoMessage() {}
+
+func (x *OrderDiscount) ProtoReflect() protoreflect.Message {
+	mi := &file_order_order_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderDiscount.ProtoReflect.Descriptor instead.
+func (*OrderDiscount) Descriptor() ([]byte, []int) {
+	return file_order_order_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OrderDiscount) GetCouponId() string {
+	if x != nil {
+		return x.CouponId
+	}
+	return ""
+}
+
+func (x *OrderDiscount) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *OrderDiscount) GetAmount() *common.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
 type CreateOrderRequest struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	Description        string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
-	DestinationAddress string                 `protobuf:"bytes,2,opt,name=destination_address,json=destinationAddress,proto3" json:"destination_address,omitempty"`
-	Items              []*OrderProduct        `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Description string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	// ID of the buyer's address book entry the order is delivered to.
+	AddressId string          `protobuf:"bytes,5,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	Items     []*OrderProduct `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	// Optional coupon, applied to the order of the seller who issued it.
+	CouponCode    string `protobuf:"bytes,4,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateOrderRequest) Reset() {
 	*x = CreateOrderRequest{}
-	mi := &file_order_order_proto_msgTypes[2]
+	mi := &file_order_order_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -223,7 +475,7 @@ func (x *CreateOrderRequest) String() string {
 func (*CreateOrderRequest) ProtoMessage() {}
 
 func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_order_proto_msgTypes[2]
+	mi := &file_order_order_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -236,7 +488,7 @@ func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
 func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_order_proto_rawDescGZIP(), []int{2}
+	return file_order_order_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CreateOrderRequest) GetDescription() string {
@@ -246,9 +498,9 @@ func (x *CreateOrderRequest) GetDescription() string {
 	return ""
 }
 
-func (x *CreateOrderRequest) GetDestinationAddress() string {
+func (x *CreateOrderRequest) GetAddressId() string {
 	if x != nil {
-		return x.DestinationAddress
+		return x.AddressId
 	}
 	return ""
 }
@@ -260,6 +512,73 @@ func (x *CreateOrderRequest) GetItems() []*OrderProduct {
 	return nil
 }
 
+func (x *CreateOrderRequest) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+type CreateOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CheckoutId    string                 `protobuf:"bytes,1,opt,name=checkout_id,json=checkoutId,proto3" json:"checkout_id,omitempty"`
+	TotalPrice    *common.Money          `protobuf:"bytes,4,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	Orders        []*Order               `protobuf:"bytes,3,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrderResponse) Reset() {
+	*x = CreateOrderResponse{}
+	mi := &file_order_order_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderResponse) ProtoMessage() {}
+
+func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_order_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_order_order_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateOrderResponse) GetCheckoutId() string {
+	if x != nil {
+		return x.CheckoutId
+	}
+	return ""
+}
+
+func (x *CreateOrderResponse) GetTotalPrice() *common.Money {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return nil
+}
+
+func (x *CreateOrderResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
 type GetOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
@@ -269,7 +588,7 @@ type GetOrderRequest struct {
 
 func (x *GetOrderRequest) Reset() {
 	*x = GetOrderRequest{}
-	mi := &file_order_order_proto_msgTypes[3]
+	mi := &file_order_order_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -281,7 +600,7 @@ func (x *GetOrderRequest) String() string {
 func (*GetOrderRequest) ProtoMessage() {}
 
 func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_order_proto_msgTypes[3]
+	mi := &file_order_order_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -294,7 +613,7 @@ func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
 func (*GetOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_order_proto_rawDescGZIP(), []int{3}
+	return file_order_order_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetOrderRequest) GetOrderId() string {
@@ -305,17 +624,29 @@ func (x *GetOrderRequest) GetOrderId() string {
 }
 
 type GetOrdersRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
-	Search        string                 `protobuf:"bytes,4,opt,name=search,proto3" json:"search,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Case-insensitive substring match on the order description.
+	Search    string `protobuf:"bytes,4,opt,name=search,proto3" json:"search,omitempty"`
+	PageSize  int32  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Orders in any of these statuses; every status when empty.
+	Statuses []OrderStatus `protobuf:"varint,7,rep,packed,name=statuses,proto3,enum=order.OrderStatus" json:"statuses,omitempty"`
+	// Inclusive lower bound on created_at.
+	CreatedAfter *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	// Exclusive upper bound on created_at.
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	// Orders containing this product.
+	ProductId string    `protobuf:"bytes,10,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Sort      OrderSort `protobuf:"varint,11,opt,name=sort,proto3,enum=order.OrderSort" json:"sort,omitempty"`
+	// Orders priced in this ISO 4217 currency. Required to sort by total price.
+	CurrencyCode  string `protobuf:"bytes,12,opt,name=currency_code,json=currencyCode,proto3" json:"currency_code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetOrdersRequest) Reset() {
 	*x = GetOrdersRequest{}
-	mi := &file_order_order_proto_msgTypes[4]
+	mi := &file_order_order_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -327,7 +658,7 @@ func (x *GetOrdersRequest) String() string {
 func (*GetOrdersRequest) ProtoMessage() {}
 
 func (x *GetOrdersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_order_proto_msgTypes[4]
+	mi := &file_order_order_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -340,26 +671,68 @@ func (x *GetOrdersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrdersRequest.ProtoReflect.Descriptor instead.
 func (*GetOrdersRequest) Descriptor() ([]byte, []int) {
-	return file_order_order_proto_rawDescGZIP(), []int{4}
+	return file_order_order_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetOrdersRequest) GetLimit() int32 {
+func (x *GetOrdersRequest) GetSearch() string {
 	if x != nil {
-		return x.Limit
+		return x.Search
 	}
-	return 0
+	return ""
 }
 
-func (x *GetOrdersRequest) GetOffset() int32 {
+func (x *GetOrdersRequest) GetPageSize() int32 {
 	if x != nil {
-		return x.Offset
+		return x.PageSize
 	}
 	return 0
 }
 
-func (x *GetOrdersRequest) GetSearch() string {
+func (x *GetOrdersRequest) GetPageToken() string {
 	if x != nil {
-		return x.Search
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetOrdersRequest) GetStatuses() []OrderStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *GetOrdersRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *GetOrdersRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *GetOrdersRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *GetOrdersRequest) GetSort() OrderSort {
+	if x != nil {
+		return x.Sort
+	}
+	return OrderSort_ORDER_SORT_UNSPECIFIED
+}
+
+func (x *GetOrdersRequest) GetCurrencyCode() string {
+	if x != nil {
+		return x.CurrencyCode
 	}
 	return ""
 }
@@ -367,13 +740,14 @@ func (x *GetOrdersRequest) GetSearch() string {
 type GetOrdersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Result        []*Order               `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetOrdersResponse) Reset() {
 	*x = GetOrdersResponse{}
-	mi := &file_order_order_proto_msgTypes[5]
+	mi := &file_order_order_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -385,7 +759,7 @@ func (x *GetOrdersResponse) String() string {
 func (*GetOrdersResponse) ProtoMessage() {}
 
 func (x *GetOrdersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_order_proto_msgTypes[5]
+	mi := &file_order_order_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -398,7 +772,7 @@ func (x *GetOrdersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrdersResponse.ProtoReflect.Descriptor instead.
 func (*GetOrdersResponse) Descriptor() ([]byte, []int) {
-	return file_order_order_proto_rawDescGZIP(), []int{5}
+	return file_order_order_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetOrdersResponse) GetResult() []*Order {
@@ -408,51 +782,465 @@ func (x *GetOrdersResponse) GetResult() []*Order {
 	return nil
 }
 
+func (x *GetOrdersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type UpdateOrderStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderStatusRequest) Reset() {
+	*x = UpdateOrderStatusRequest{}
+	mi := &file_order_order_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderStatusRequest) ProtoMessage() {}
+
+func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_order_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_order_order_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateOrderStatusRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *UpdateOrderStatusRequest) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_order_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CancelOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RejectOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectOrderRequest) Reset() {
+	*x = RejectOrderRequest{}
+	mi := &file_order_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectOrderRequest) ProtoMessage() {}
+
+func (x *RejectOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectOrderRequest.ProtoReflect.Descriptor instead.
+func (*RejectOrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RejectOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *RejectOrderRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// OrderStatusEvent is one entry of an order's status history. The first entry
+// records the order's creation and has no from_status.
+type OrderStatusEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromStatus    OrderStatus            `protobuf:"varint,1,opt,name=from_status,json=fromStatus,proto3,enum=order.OrderStatus" json:"from_status,omitempty"`
+	ToStatus      OrderStatus            `protobuf:"varint,2,opt,name=to_status,json=toStatus,proto3,enum=order.OrderStatus" json:"to_status,omitempty"`
+	ActorId       string                 `protobuf:"bytes,3,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ActorType     string                 `protobuf:"bytes,4,opt,name=actor_type,json=actorType,proto3" json:"actor_type,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusEvent) Reset() {
+	*x = OrderStatusEvent{}
+	mi := &file_order_order_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusEvent) ProtoMessage() {}
+
+func (x *OrderStatusEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_order_order_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusEvent.ProtoReflect.Descriptor instead.
+func (*OrderStatusEvent) Descriptor() ([]byte, []int) {
+	return file_order_order_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *OrderStatusEvent) GetFromStatus() OrderStatus {
+	if x != nil {
+		return x.FromStatus
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *OrderStatusEvent) GetToStatus() OrderStatus {
+	if x != nil {
+		return x.ToStatus
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *OrderStatusEvent) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *OrderStatusEvent) GetActorType() string {
+	if x != nil {
+		return x.ActorType
+	}
+	return ""
+}
+
+func (x *OrderStatusEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OrderStatusEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type GetOrderTimelineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderTimelineRequest) Reset() {
+	*x = GetOrderTimelineRequest{}
+	mi := &file_order_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderTimelineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderTimelineRequest) ProtoMessage() {}
+
+func (x *GetOrderTimelineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderTimelineRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderTimelineRequest) Descriptor() ([]byte, []int) {
+	return file_order_order_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetOrderTimelineRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetOrderTimelineResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Oldest first.
+	Events        []*OrderStatusEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderTimelineResponse) Reset() {
+	*x = GetOrderTimelineResponse{}
+	mi := &file_order_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderTimelineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderTimelineResponse) ProtoMessage() {}
+
+func (x *GetOrderTimelineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderTimelineResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderTimelineResponse) Descriptor() ([]byte, []int) {
+	return file_order_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetOrderTimelineResponse) GetEvents() []*OrderStatusEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
 var File_order_order_proto protoreflect.FileDescriptor
 
 const file_order_order_proto_rawDesc = "" +
 	"\n" +
-	"\x11order/order.proto\x12\x05order\x1a\x0fuser/user.proto\x1a\x12common/types.proto\x1a\x1cgoogle/api/annotations.proto\"I\n" +
+	"\x11order/order.proto\x12\x05order\x1a\x0fuser/user.proto\x1a\x12common/types.proto\x1a\x15payment/payment.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xcf\x01\n" +
 	"\fOrderProduct\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x02 \x01(\x03R\bquantity\"\xf9\x02\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12,\n" +
+	"\n" +
+	"unit_price\x18\a \x01(\v2\r.common.MoneyR\tunitPrice\x12\x1b\n" +
+	"\tseller_id\x18\x05 \x01(\tR\bsellerId\x12\x1f\n" +
+	"\vseller_name\x18\x06 \x01(\tR\n" +
+	"sellerNameJ\x04\b\x04\x10\x05\"\xfa\x04\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12%\n" +
 	"\x0esource_address\x18\x03 \x01(\tR\rsourceAddress\x12/\n" +
-	"\x13destination_address\x18\x04 \x01(\tR\x12destinationAddress\x12\x1f\n" +
-	"\vtotal_price\x18\x05 \x01(\x03R\n" +
+	"\x13destination_address\x18\x04 \x01(\tR\x12destinationAddress\x12.\n" +
+	"\vtotal_price\x18\r \x01(\v2\r.common.MoneyR\n" +
 	"totalPrice\x12\"\n" +
 	"\x06seller\x18\x06 \x01(\v2\n" +
 	".user.UserR\x06seller\x12 \n" +
 	"\x05buyer\x18\a \x01(\v2\n" +
-	".user.UserR\x05buyer\x12\x16\n" +
-	"\x06status\x18\b \x01(\tR\x06status\x12)\n" +
+	".user.UserR\x05buyer\x12)\n" +
 	"\x05items\x18\t \x03(\v2\x13.order.OrderProductR\x05items\x12\x1d\n" +
 	"\n" +
 	"created_at\x18\n" +
 	" \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\v \x01(\tR\tupdatedAt\"\x92\x01\n" +
+	"updated_at\x18\v \x01(\tR\tupdatedAt\x12\x1f\n" +
+	"\vcheckout_id\x18\f \x01(\tR\n" +
+	"checkoutId\x12)\n" +
+	"\bsubtotal\x18\x0e \x01(\v2\r.common.MoneyR\bsubtotal\x122\n" +
+	"\tdiscounts\x18\x0f \x03(\v2\x14.order.OrderDiscountR\tdiscounts\x12=\n" +
+	"\x0epayment_status\x18\x10 \x01(\x0e2\x16.payment.PaymentStatusR\rpaymentStatus\x125\n" +
+	"\forder_status\x18\x11 \x01(\x0e2\x12.order.OrderStatusR\vorderStatusJ\x04\b\x05\x10\x06J\x04\b\b\x10\tR\x06status\"g\n" +
+	"\rOrderDiscount\x12\x1b\n" +
+	"\tcoupon_id\x18\x01 \x01(\tR\bcouponId\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12%\n" +
+	"\x06amount\x18\x03 \x01(\v2\r.common.MoneyR\x06amount\"\xbc\x01\n" +
 	"\x12CreateOrderRequest\x12 \n" +
-	"\vdescription\x18\x01 \x01(\tR\vdescription\x12/\n" +
-	"\x13destination_address\x18\x02 \x01(\tR\x12destinationAddress\x12)\n" +
-	"\x05items\x18\x03 \x03(\v2\x13.order.OrderProductR\x05items\",\n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"address_id\x18\x05 \x01(\tR\taddressId\x12)\n" +
+	"\x05items\x18\x03 \x03(\v2\x13.order.OrderProductR\x05items\x12\x1f\n" +
+	"\vcoupon_code\x18\x04 \x01(\tR\n" +
+	"couponCodeJ\x04\b\x02\x10\x03R\x13destination_address\"\x92\x01\n" +
+	"\x13CreateOrderResponse\x12\x1f\n" +
+	"\vcheckout_id\x18\x01 \x01(\tR\n" +
+	"checkoutId\x12.\n" +
+	"\vtotal_price\x18\x04 \x01(\v2\r.common.MoneyR\n" +
+	"totalPrice\x12$\n" +
+	"\x06orders\x18\x03 \x03(\v2\f.order.OrderR\x06ordersJ\x04\b\x02\x10\x03\",\n" +
 	"\x0fGetOrderRequest\x12\x19\n" +
-	"\border_id\x18\x01 \x01(\tR\aorderId\"X\n" +
-	"\x10GetOrdersRequest\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x16\n" +
-	"\x06search\x18\x04 \x01(\tR\x06search\"9\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"\x9f\x03\n" +
+	"\x10GetOrdersRequest\x12\x16\n" +
+	"\x06search\x18\x04 \x01(\tR\x06search\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\x12.\n" +
+	"\bstatuses\x18\a \x03(\x0e2\x12.order.OrderStatusR\bstatuses\x12?\n" +
+	"\rcreated_after\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\n" +
+	" \x01(\tR\tproductId\x12$\n" +
+	"\x04sort\x18\v \x01(\x0e2\x10.order.OrderSortR\x04sort\x12#\n" +
+	"\rcurrency_code\x18\f \x01(\tR\fcurrencyCodeJ\x04\b\x02\x10\x03J\x04\b\x03\x10\x04R\x05limitR\x06offset\"a\n" +
 	"\x11GetOrdersResponse\x12$\n" +
-	"\x06result\x18\x01 \x03(\v2\f.order.OrderR\x06result2\x82\x02\n" +
-	"\fOrderService\x12M\n" +
-	"\vCreateOrder\x12\x19.order.CreateOrderRequest\x1a\f.order.Order\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"\x06result\x18\x01 \x03(\v2\f.order.OrderR\x06result\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"a\n" +
+	"\x18UpdateOrderStatusRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12*\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x12.order.OrderStatusR\x06status\"G\n" +
+	"\x12CancelOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"G\n" +
+	"\x12RejectOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x85\x02\n" +
+	"\x10OrderStatusEvent\x123\n" +
+	"\vfrom_status\x18\x01 \x01(\x0e2\x12.order.OrderStatusR\n" +
+	"fromStatus\x12/\n" +
+	"\tto_status\x18\x02 \x01(\x0e2\x12.order.OrderStatusR\btoStatus\x12\x19\n" +
+	"\bactor_id\x18\x03 \x01(\tR\aactorId\x12\x1d\n" +
+	"\n" +
+	"actor_type\x18\x04 \x01(\tR\tactorType\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"4\n" +
+	"\x17GetOrderTimelineRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"K\n" +
+	"\x18GetOrderTimelineResponse\x12/\n" +
+	"\x06events\x18\x01 \x03(\v2\x17.order.OrderStatusEventR\x06events*\xe4\x01\n" +
+	"\vOrderStatus\x12\x1c\n" +
+	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14ORDER_STATUS_PENDING\x10\x01\x12\x19\n" +
+	"\x15ORDER_STATUS_ACCEPTED\x10\x02\x12\x18\n" +
+	"\x14ORDER_STATUS_SHIPPED\x10\x03\x12\x1a\n" +
+	"\x16ORDER_STATUS_DELIVERED\x10\x04\x12\x1a\n" +
+	"\x16ORDER_STATUS_CANCELLED\x10\x05\x12\x19\n" +
+	"\x15ORDER_STATUS_REJECTED\x10\x06\x12\x15\n" +
+	"\x11ORDER_STATUS_PAID\x10\a*\x96\x01\n" +
+	"\tOrderSort\x12\x1a\n" +
+	"\x16ORDER_SORT_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11ORDER_SORT_NEWEST\x10\x01\x12\x15\n" +
+	"\x11ORDER_SORT_OLDEST\x10\x02\x12\x1e\n" +
+	"\x1aORDER_SORT_TOTAL_PRICE_ASC\x10\x03\x12\x1f\n" +
+	"\x1bORDER_SORT_TOTAL_PRICE_DESC\x10\x042\xbc\x05\n" +
+	"\fOrderService\x12[\n" +
+	"\vCreateOrder\x12\x19.order.CreateOrderRequest\x1a\x1a.order.CreateOrderResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
 	"/v1/orders\x12O\n" +
 	"\bGetOrder\x12\x16.order.GetOrderRequest\x1a\f.order.Order\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/orders/{order_id}\x12R\n" +
 	"\tGetOrders\x12\x17.order.GetOrdersRequest\x1a\x18.order.GetOrdersResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
-	"/v1/ordersB7Z5github.com/situmorangbastian/skyros/proto/order;orderb\x06proto3"
+	"/v1/orders\x12k\n" +
+	"\x11UpdateOrderStatus\x12\x1f.order.UpdateOrderStatusRequest\x1a\f.order.Order\"'\x82\xd3\xe4\x93\x02!:\x01*2\x1c/v1/orders/{order_id}/status\x12_\n" +
+	"\vCancelOrder\x12\x19.order.CancelOrderRequest\x1a\f.order.Order\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/orders/{order_id}/cancel\x12_\n" +
+	"\vRejectOrder\x12\x19.order.RejectOrderRequest\x1a\f.order.Order\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/orders/{order_id}/reject\x12{\n" +
+	"\x10GetOrderTimeline\x12\x1e.order.GetOrderTimelineRequest\x1a\x1f.order.GetOrderTimelineResponse\"&\x82\xd3\xe4\x93\x02 \x12\x1e/v1/orders/{order_id}/timelineB7Z5github.com/situmorangbastian/skyros/proto/order;orderb\x06proto3"
 
 var (
 	file_order_order_proto_rawDescOnce sync.Once
@@ -466,33 +1254,73 @@ func file_order_order_proto_rawDescGZIP() []byte {
 	return file_order_order_proto_rawDescData
 }
 
-var file_order_order_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_order_order_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_order_order_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_order_order_proto_goTypes = []any{
-	(*OrderProduct)(nil),       // 0: order.OrderProduct
-	(*Order)(nil),              // 1: order.Order
-	(*CreateOrderRequest)(nil), // 2: order.CreateOrderRequest
-	(*GetOrderRequest)(nil),    // 3: order.GetOrderRequest
-	(*GetOrdersRequest)(nil),   // 4: order.GetOrdersRequest
-	(*GetOrdersResponse)(nil),  // 5: order.GetOrdersResponse
-	(*user.User)(nil),          // 6: user.User
+	(OrderStatus)(0),                 // 0: order.OrderStatus
+	(OrderSort)(0),                   // 1: order.OrderSort
+	(*OrderProduct)(nil),             // 2: order.OrderProduct
+	(*Order)(nil),                    // 3: order.Order
+	(*OrderDiscount)(nil),            // 4: order.OrderDiscount
+	(*CreateOrderRequest)(nil),       // 5: order.CreateOrderRequest
+	(*CreateOrderResponse)(nil),      // 6: order.CreateOrderResponse
+	(*GetOrderRequest)(nil),          // 7: order.GetOrderRequest
+	(*GetOrdersRequest)(nil),         // 8: order.GetOrdersRequest
+	(*GetOrdersResponse)(nil),        // 9: order.GetOrdersResponse
+	(*UpdateOrderStatusRequest)(nil), // 10: order.UpdateOrderStatusRequest
+	(*CancelOrderRequest)(nil),       // 11: order.CancelOrderRequest
+	(*RejectOrderRequest)(nil),       // 12: order.RejectOrderRequest
+	(*OrderStatusEvent)(nil),         // 13: order.OrderStatusEvent
+	(*GetOrderTimelineRequest)(nil),  // 14: order.GetOrderTimelineRequest
+	(*GetOrderTimelineResponse)(nil), // 15: order.GetOrderTimelineResponse
+	(*common.Money)(nil),             // 16: common.Money
+	(*user.User)(nil),                // 17: user.User
+	(payment.PaymentStatus)(0),       // 18: payment.PaymentStatus
+	(*timestamppb.Timestamp)(nil),    // 19: google.protobuf.Timestamp
 }
 var file_order_order_proto_depIdxs = []int32{
-	6, // 0: order.Order.seller:type_name -> user.User
-	6, // 1: order.Order.buyer:type_name -> user.User
-	0, // 2: order.Order.items:type_name -> order.OrderProduct
-	0, // 3: order.CreateOrderRequest.items:type_name -> order.OrderProduct
-	1, // 4: order.GetOrdersResponse.result:type_name -> order.Order
-	2, // 5: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
-	3, // 6: order.OrderService.GetOrder:input_type -> order.GetOrderRequest
-	4, // 7: order.OrderService.GetOrders:input_type -> order.GetOrdersRequest
-	1, // 8: order.OrderService.CreateOrder:output_type -> order.Order
-	1, // 9: order.OrderService.GetOrder:output_type -> order.Order
-	5, // 10: order.OrderService.GetOrders:output_type -> order.GetOrdersResponse
-	8, // [8:11] is the sub-list for method output_type
-	5, // [5:8] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	16, // 0: order.OrderProduct.unit_price:type_name -> common.Money
+	16, // 1: order.Order.total_price:type_name -> common.Money
+	17, // 2: order.Order.seller:type_name -> user.User
+	17, // 3: order.Order.buyer:type_name -> user.User
+	2,  // 4: order.Order.items:type_name -> order.OrderProduct
+	16, // 5: order.Order.subtotal:type_name -> common.Money
+	4,  // 6: order.Order.discounts:type_name -> order.OrderDiscount
+	18, // 7: order.Order.payment_status:type_name -> payment.PaymentStatus
+	0,  // 8: order.Order.order_status:type_name -> order.OrderStatus
+	16, // 9: order.OrderDiscount.amount:type_name -> common.Money
+	2,  // 10: order.CreateOrderRequest.items:type_name -> order.OrderProduct
+	16, // 11: order.CreateOrderResponse.total_price:type_name -> common.Money
+	3,  // 12: order.CreateOrderResponse.orders:type_name -> order.Order
+	0,  // 13: order.GetOrdersRequest.statuses:type_name -> order.OrderStatus
+	19, // 14: order.GetOrdersRequest.created_after:type_name -> google.protobuf.Timestamp
+	19, // 15: order.GetOrdersRequest.created_before:type_name -> google.protobuf.Timestamp
+	1,  // 16: order.GetOrdersRequest.sort:type_name -> order.OrderSort
+	3,  // 17: order.GetOrdersResponse.result:type_name -> order.Order
+	0,  // 18: order.UpdateOrderStatusRequest.status:type_name -> order.OrderStatus
+	0,  // 19: order.OrderStatusEvent.from_status:type_name -> order.OrderStatus
+	0,  // 20: order.OrderStatusEvent.to_status:type_name -> order.OrderStatus
+	19, // 21: order.OrderStatusEvent.created_at:type_name -> google.protobuf.Timestamp
+	13, // 22: order.GetOrderTimelineResponse.events:type_name -> order.OrderStatusEvent
+	5,  // 23: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
+	7,  // 24: order.OrderService.GetOrder:input_type -> order.GetOrderRequest
+	8,  // 25: order.OrderService.GetOrders:input_type -> order.GetOrdersRequest
+	10, // 26: order.OrderService.UpdateOrderStatus:input_type -> order.UpdateOrderStatusRequest
+	11, // 27: order.OrderService.CancelOrder:input_type -> order.CancelOrderRequest
+	12, // 28: order.OrderService.RejectOrder:input_type -> order.RejectOrderRequest
+	14, // 29: order.OrderService.GetOrderTimeline:input_type -> order.GetOrderTimelineRequest
+	6,  // 30: order.OrderService.CreateOrder:output_type -> order.CreateOrderResponse
+	3,  // 31: order.OrderService.GetOrder:output_type -> order.Order
+	9,  // 32: order.OrderService.GetOrders:output_type -> order.GetOrdersResponse
+	3,  // 33: order.OrderService.UpdateOrderStatus:output_type -> order.Order
+	3,  // 34: order.OrderService.CancelOrder:output_type -> order.Order
+	3,  // 35: order.OrderService.RejectOrder:output_type -> order.Order
+	15, // 36: order.OrderService.GetOrderTimeline:output_type -> order.GetOrderTimelineResponse
+	30, // [30:37] is the sub-list for method output_type
+	23, // [23:30] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_order_order_proto_init() }
@@ -505,13 +1333,14 @@ func file_order_order_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order_order_proto_rawDesc), len(file_order_order_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      2,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_order_order_proto_goTypes,
 		DependencyIndexes: file_order_order_proto_depIdxs,
+		EnumInfos:         file_order_order_proto_enumTypes,
 		MessageInfos:      file_order_order_proto_msgTypes,
 	}.Build()
 	File_order_order_proto = out.File
@@ -0,0 +1,635 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: payment/payment.proto
+
+package payment
+
+import (
+	common "github.com/situmorangbastian/skyros/proto/common"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PaymentStatus int32
+
+const (
+	// No payment has been attempted.
+	PaymentStatus_PAYMENT_STATUS_UNSPECIFIED PaymentStatus = 0
+	// Authorized by the provider, waiting for CapturePayment.
+	PaymentStatus_PAYMENT_STATUS_REQUIRES_CAPTURE PaymentStatus = 1
+	// Submitted to the provider, settled later through a webhook.
+	PaymentStatus_PAYMENT_STATUS_PROCESSING PaymentStatus = 2
+	PaymentStatus_PAYMENT_STATUS_SUCCEEDED  PaymentStatus = 3
+	PaymentStatus_PAYMENT_STATUS_FAILED     PaymentStatus = 4
+	PaymentStatus_PAYMENT_STATUS_REFUNDED   PaymentStatus = 5
+)
+
+// Enum value maps for PaymentStatus.
+var (
+	PaymentStatus_name = map[int32]string{
+		0: "PAYMENT_STATUS_UNSPECIFIED",
+		1: "PAYMENT_STATUS_REQUIRES_CAPTURE",
+		2: "PAYMENT_STATUS_PROCESSING",
+		3: "PAYMENT_STATUS_SUCCEEDED",
+		4: "PAYMENT_STATUS_FAILED",
+		5: "PAYMENT_STATUS_REFUNDED",
+	}
+	PaymentStatus_value = map[string]int32{
+		"PAYMENT_STATUS_UNSPECIFIED":      0,
+		"PAYMENT_STATUS_REQUIRES_CAPTURE": 1,
+		"PAYMENT_STATUS_PROCESSING":       2,
+		"PAYMENT_STATUS_SUCCEEDED":        3,
+		"PAYMENT_STATUS_FAILED":           4,
+		"PAYMENT_STATUS_REFUNDED":         5,
+	}
+)
+
+func (x PaymentStatus) Enum() *PaymentStatus {
+	p := new(PaymentStatus)
+	*p = x
+	return p
+}
+
+func (x PaymentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PaymentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_payment_payment_proto_enumTypes[0].Descriptor()
+}
+
+func (PaymentStatus) Type() protoreflect.EnumType {
+	return &file_payment_payment_proto_enumTypes[0]
+}
+
+func (x PaymentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PaymentStatus.Descriptor instead.
+func (PaymentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_payment_payment_proto_rawDescGZIP(), []int{0}
+}
+
+// PaymentIntent is one attempt to pay for an order. An order has at most one intent
+// that is not failed.
+type PaymentIntent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Provider      string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Amount        *common.Money          `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status        PaymentStatus          `protobuf:"varint,5,opt,name=status,proto3,enum=payment.PaymentStatus" json:"status,omitempty"`
+	FailureReason string                 `protobuf:"bytes,6,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentIntent) Reset() {
+	*x = PaymentIntent{}
+	mi := &file_payment_payment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentIntent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentIntent) ProtoMessage() {}
+
+func (x *PaymentIntent) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_payment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentIntent.ProtoReflect.Descriptor instead.
+func (*PaymentIntent) Descriptor() ([]byte, []int) {
+	return file_payment_payment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PaymentIntent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PaymentIntent) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PaymentIntent) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *PaymentIntent) GetAmount() *common.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *PaymentIntent) GetStatus() PaymentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PaymentStatus_PAYMENT_STATUS_UNSPECIFIED
+}
+
+func (x *PaymentIntent) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *PaymentIntent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *PaymentIntent) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreatePaymentIntentRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Provider specific token of the buyer's payment method.
+	PaymentMethod string `protobuf:"bytes,2,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePaymentIntentRequest) Reset() {
+	*x = CreatePaymentIntentRequest{}
+	mi := &file_payment_payment_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePaymentIntentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePaymentIntentRequest) ProtoMessage() {}
+
+func (x *CreatePaymentIntentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_payment_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePaymentIntentRequest.ProtoReflect.Descriptor instead.
+func (*CreatePaymentIntentRequest) Descriptor() ([]byte, []int) {
+	return file_payment_payment_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreatePaymentIntentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CreatePaymentIntentRequest) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+type CapturePaymentRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PaymentIntentId string                 `protobuf:"bytes,1,opt,name=payment_intent_id,json=paymentIntentId,proto3" json:"payment_intent_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CapturePaymentRequest) Reset() {
+	*x = CapturePaymentRequest{}
+	mi := &file_payment_payment_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CapturePaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapturePaymentRequest) ProtoMessage() {}
+
+func (x *CapturePaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_payment_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapturePaymentRequest.ProtoReflect.Descriptor instead.
+func (*CapturePaymentRequest) Descriptor() ([]byte, []int) {
+	return file_payment_payment_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CapturePaymentRequest) GetPaymentIntentId() string {
+	if x != nil {
+		return x.PaymentIntentId
+	}
+	return ""
+}
+
+type RefundPaymentRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PaymentIntentId string                 `protobuf:"bytes,1,opt,name=payment_intent_id,json=paymentIntentId,proto3" json:"payment_intent_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RefundPaymentRequest) Reset() {
+	*x = RefundPaymentRequest{}
+	mi := &file_payment_payment_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundPaymentRequest) ProtoMessage() {}
+
+func (x *RefundPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_payment_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundPaymentRequest.ProtoReflect.Descriptor instead.
+func (*RefundPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_payment_payment_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RefundPaymentRequest) GetPaymentIntentId() string {
+	if x != nil {
+		return x.PaymentIntentId
+	}
+	return ""
+}
+
+type GetPaymentIntentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentIntentsRequest) Reset() {
+	*x = GetPaymentIntentsRequest{}
+	mi := &file_payment_payment_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentIntentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentIntentsRequest) ProtoMessage() {}
+
+func (x *GetPaymentIntentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_payment_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentIntentsRequest.ProtoReflect.Descriptor instead.
+func (*GetPaymentIntentsRequest) Descriptor() ([]byte, []int) {
+	return file_payment_payment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPaymentIntentsRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetPaymentIntentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        []*PaymentIntent       `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentIntentsResponse) Reset() {
+	*x = GetPaymentIntentsResponse{}
+	mi := &file_payment_payment_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentIntentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentIntentsResponse) ProtoMessage() {}
+
+func (x *GetPaymentIntentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_payment_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentIntentsResponse.ProtoReflect.Descriptor instead.
+func (*GetPaymentIntentsResponse) Descriptor() ([]byte, []int) {
+	return file_payment_payment_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetPaymentIntentsResponse) GetResult() []*PaymentIntent {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type HandlePaymentWebhookRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Provider string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	// Request body exactly as sent by the provider; the signature covers these bytes.
+	Payload       []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Signature     string `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandlePaymentWebhookRequest) Reset() {
+	*x = HandlePaymentWebhookRequest{}
+	mi := &file_payment_payment_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandlePaymentWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandlePaymentWebhookRequest) ProtoMessage() {}
+
+func (x *HandlePaymentWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_payment_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandlePaymentWebhookRequest.ProtoReflect.Descriptor instead.
+func (*HandlePaymentWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_payment_payment_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HandlePaymentWebhookRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *HandlePaymentWebhookRequest) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *HandlePaymentWebhookRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type HandlePaymentWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandlePaymentWebhookResponse) Reset() {
+	*x = HandlePaymentWebhookResponse{}
+	mi := &file_payment_payment_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandlePaymentWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandlePaymentWebhookResponse) ProtoMessage() {}
+
+func (x *HandlePaymentWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_payment_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandlePaymentWebhookResponse.ProtoReflect.Descriptor instead.
+func (*HandlePaymentWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_payment_payment_proto_rawDescGZIP(), []int{7}
+}
+
+var File_payment_payment_proto protoreflect.FileDescriptor
+
+const file_payment_payment_proto_rawDesc = "" +
+	"\n" +
+	"\x15payment/payment.proto\x12\apayment\x1a\x12common/types.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xca\x02\n" +
+	"\rPaymentIntent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12%\n" +
+	"\x06amount\x18\x04 \x01(\v2\r.common.MoneyR\x06amount\x12.\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x16.payment.PaymentStatusR\x06status\x12%\n" +
+	"\x0efailure_reason\x18\x06 \x01(\tR\rfailureReason\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"^\n" +
+	"\x1aCreatePaymentIntentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12%\n" +
+	"\x0epayment_method\x18\x02 \x01(\tR\rpaymentMethod\"C\n" +
+	"\x15CapturePaymentRequest\x12*\n" +
+	"\x11payment_intent_id\x18\x01 \x01(\tR\x0fpaymentIntentId\"B\n" +
+	"\x14RefundPaymentRequest\x12*\n" +
+	"\x11payment_intent_id\x18\x01 \x01(\tR\x0fpaymentIntentId\"5\n" +
+	"\x18GetPaymentIntentsRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"K\n" +
+	"\x19GetPaymentIntentsResponse\x12.\n" +
+	"\x06result\x18\x01 \x03(\v2\x16.payment.PaymentIntentR\x06result\"q\n" +
+	"\x1bHandlePaymentWebhookRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x18\n" +
+	"\apayload\x18\x02 \x01(\fR\apayload\x12\x1c\n" +
+	"\tsignature\x18\x03 \x01(\tR\tsignature\"\x1e\n" +
+	"\x1cHandlePaymentWebhookResponse*\xc9\x01\n" +
+	"\rPaymentStatus\x12\x1e\n" +
+	"\x1aPAYMENT_STATUS_UNSPECIFIED\x10\x00\x12#\n" +
+	"\x1fPAYMENT_STATUS_REQUIRES_CAPTURE\x10\x01\x12\x1d\n" +
+	"\x19PAYMENT_STATUS_PROCESSING\x10\x02\x12\x1c\n" +
+	"\x18PAYMENT_STATUS_SUCCEEDED\x10\x03\x12\x19\n" +
+	"\x15PAYMENT_STATUS_FAILED\x10\x04\x12\x1b\n" +
+	"\x17PAYMENT_STATUS_REFUNDED\x10\x052\xee\x04\n" +
+	"\x0ePaymentService\x12}\n" +
+	"\x13CreatePaymentIntent\x12#.payment.CreatePaymentIntentRequest\x1a\x16.payment.PaymentIntent\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/v1/orders/{order_id}/payments\x12\x82\x01\n" +
+	"\x11GetPaymentIntents\x12!.payment.GetPaymentIntentsRequest\x1a\".payment.GetPaymentIntentsResponse\"&\x82\xd3\xe4\x93\x02 \x12\x1e/v1/orders/{order_id}/payments\x12z\n" +
+	"\x0eCapturePayment\x12\x1e.payment.CapturePaymentRequest\x1a\x16.payment.PaymentIntent\"0\x82\xd3\xe4\x93\x02*\"(/v1/payments/{payment_intent_id}/capture\x12w\n" +
+	"\rRefundPayment\x12\x1d.payment.RefundPaymentRequest\x1a\x16.payment.PaymentIntent\"/\x82\xd3\xe4\x93\x02)\"'/v1/payments/{payment_intent_id}/refund\x12c\n" +
+	"\x14HandlePaymentWebhook\x12$.payment.HandlePaymentWebhookRequest\x1a%.payment.HandlePaymentWebhookResponseB;Z9github.com/situmorangbastian/skyros/proto/payment;paymentb\x06proto3"
+
+var (
+	file_payment_payment_proto_rawDescOnce sync.Once
+	file_payment_payment_proto_rawDescData []byte
+)
+
+func file_payment_payment_proto_rawDescGZIP() []byte {
+	file_payment_payment_proto_rawDescOnce.Do(func() {
+		file_payment_payment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_payment_payment_proto_rawDesc), len(file_payment_payment_proto_rawDesc)))
+	})
+	return file_payment_payment_proto_rawDescData
+}
+
+var file_payment_payment_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_payment_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_payment_payment_proto_goTypes = []any{
+	(PaymentStatus)(0),                   // 0: payment.PaymentStatus
+	(*PaymentIntent)(nil),                // 1: payment.PaymentIntent
+	(*CreatePaymentIntentRequest)(nil),   // 2: payment.CreatePaymentIntentRequest
+	(*CapturePaymentRequest)(nil),        // 3: payment.CapturePaymentRequest
+	(*RefundPaymentRequest)(nil),         // 4: payment.RefundPaymentRequest
+	(*GetPaymentIntentsRequest)(nil),     // 5: payment.GetPaymentIntentsRequest
+	(*GetPaymentIntentsResponse)(nil),    // 6: payment.GetPaymentIntentsResponse
+	(*HandlePaymentWebhookRequest)(nil),  // 7: payment.HandlePaymentWebhookRequest
+	(*HandlePaymentWebhookResponse)(nil), // 8: payment.HandlePaymentWebhookResponse
+	(*common.Money)(nil),                 // 9: common.Money
+	(*timestamppb.Timestamp)(nil),        // 10: google.protobuf.Timestamp
+}
+var file_payment_payment_proto_depIdxs = []int32{
+	9,  // 0: payment.PaymentIntent.amount:type_name -> common.Money
+	0,  // 1: payment.PaymentIntent.status:type_name -> payment.PaymentStatus
+	10, // 2: payment.PaymentIntent.created_at:type_name -> google.protobuf.Timestamp
+	10, // 3: payment.PaymentIntent.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 4: payment.GetPaymentIntentsResponse.result:type_name -> payment.PaymentIntent
+	2,  // 5: payment.PaymentService.CreatePaymentIntent:input_type -> payment.CreatePaymentIntentRequest
+	5,  // 6: payment.PaymentService.GetPaymentIntents:input_type -> payment.GetPaymentIntentsRequest
+	3,  // 7: payment.PaymentService.CapturePayment:input_type -> payment.CapturePaymentRequest
+	4,  // 8: payment.PaymentService.RefundPayment:input_type -> payment.RefundPaymentRequest
+	7,  // 9: payment.PaymentService.HandlePaymentWebhook:input_type -> payment.HandlePaymentWebhookRequest
+	1,  // 10: payment.PaymentService.CreatePaymentIntent:output_type -> payment.PaymentIntent
+	6,  // 11: payment.PaymentService.GetPaymentIntents:output_type -> payment.GetPaymentIntentsResponse
+	1,  // 12: payment.PaymentService.CapturePayment:output_type -> payment.PaymentIntent
+	1,  // 13: payment.PaymentService.RefundPayment:output_type -> payment.PaymentIntent
+	8,  // 14: payment.PaymentService.HandlePaymentWebhook:output_type -> payment.HandlePaymentWebhookResponse
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_payment_payment_proto_init() }
+func file_payment_payment_proto_init() {
+	if File_payment_payment_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_payment_payment_proto_rawDesc), len(file_payment_payment_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_payment_payment_proto_goTypes,
+		DependencyIndexes: file_payment_payment_proto_depIdxs,
+		EnumInfos:         file_payment_payment_proto_enumTypes,
+		MessageInfos:      file_payment_payment_proto_msgTypes,
+	}.Build()
+	File_payment_payment_proto = out.File
+	file_payment_payment_proto_goTypes = nil
+	file_payment_payment_proto_depIdxs = nil
+}
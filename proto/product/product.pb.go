@@ -7,11 +7,13 @@
 package product
 
 import (
-	_ "github.com/situmorangbastian/skyros/proto/common"
+	common "github.com/situmorangbastian/skyros/proto/common"
 	user "github.com/situmorangbastian/skyros/proto/user"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -24,13 +26,70 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ProductSort int32
+
+const (
+	// Relevance when searching, newest first otherwise.
+	ProductSort_PRODUCT_SORT_UNSPECIFIED ProductSort = 0
+	ProductSort_PRODUCT_SORT_RELEVANCE   ProductSort = 1
+	ProductSort_PRODUCT_SORT_NEWEST      ProductSort = 2
+	ProductSort_PRODUCT_SORT_PRICE_ASC   ProductSort = 3
+	ProductSort_PRODUCT_SORT_PRICE_DESC  ProductSort = 4
+)
+
+// Enum value maps for ProductSort.
+var (
+	ProductSort_name = map[int32]string{
+		0: "PRODUCT_SORT_UNSPECIFIED",
+		1: "PRODUCT_SORT_RELEVANCE",
+		2: "PRODUCT_SORT_NEWEST",
+		3: "PRODUCT_SORT_PRICE_ASC",
+		4: "PRODUCT_SORT_PRICE_DESC",
+	}
+	ProductSort_value = map[string]int32{
+		"PRODUCT_SORT_UNSPECIFIED": 0,
+		"PRODUCT_SORT_RELEVANCE":   1,
+		"PRODUCT_SORT_NEWEST":      2,
+		"PRODUCT_SORT_PRICE_ASC":   3,
+		"PRODUCT_SORT_PRICE_DESC":  4,
+	}
+)
+
+func (x ProductSort) Enum() *ProductSort {
+	p := new(ProductSort)
+	*p = x
+	return p
+}
+
+func (x ProductSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProductSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_product_product_proto_enumTypes[0].Descriptor()
+}
+
+func (ProductSort) Type() protoreflect.EnumType {
+	return &file_product_product_proto_enumTypes[0]
+}
+
+func (x ProductSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProductSort.Descriptor instead.
+func (ProductSort) EnumDescriptor() ([]byte, []int) {
+	return file_product_product_proto_rawDescGZIP(), []int{0}
+}
+
 type Product struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         int32                  `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	Price         *common.Money          `protobuf:"bytes,7,opt,name=price,proto3" json:"price,omitempty"`
 	Seller        *user.User             `protobuf:"bytes,5,opt,name=seller,proto3" json:"seller,omitempty"`
+	Stock         int64                  `protobuf:"varint,6,opt,name=stock,proto3" json:"stock,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -86,11 +145,11 @@ func (x *Product) GetDescription() string {
 	return ""
 }
 
-func (x *Product) GetPrice() int32 {
+func (x *Product) GetPrice() *common.Money {
 	if x != nil {
 		return x.Price
 	}
-	return 0
+	return nil
 }
 
 func (x *Product) GetSeller() *user.User {
@@ -100,6 +159,13 @@ func (x *Product) GetSeller() *user.User {
 	return nil
 }
 
+func (x *Product) GetStock() int64 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
 type GetProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -145,11 +211,19 @@ func (x *GetProductRequest) GetId() string {
 }
 
 type GetProductsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
-	Search        string                 `protobuf:"bytes,4,opt,name=search,proto3" json:"search,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ids   []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// Full-text query over name and description, e.g. `red shoes -leather`.
+	Search    string `protobuf:"bytes,4,opt,name=search,proto3" json:"search,omitempty"`
+	PageSize  int32  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Price bounds in minor units. Price bounds and price sorts require currency_code.
+	MinPrice *int64      `protobuf:"varint,7,opt,name=min_price,json=minPrice,proto3,oneof" json:"min_price,omitempty"`
+	MaxPrice *int64      `protobuf:"varint,8,opt,name=max_price,json=maxPrice,proto3,oneof" json:"max_price,omitempty"`
+	SellerId string      `protobuf:"bytes,9,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	Sort     ProductSort `protobuf:"varint,10,opt,name=sort,proto3,enum=product.ProductSort" json:"sort,omitempty"`
+	// Products priced in this ISO 4217 currency.
+	CurrencyCode  string `protobuf:"bytes,11,opt,name=currency_code,json=currencyCode,proto3" json:"currency_code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -191,37 +265,241 @@ func (x *GetProductsRequest) GetIds() []string {
 	return nil
 }
 
-func (x *GetProductsRequest) GetLimit() int32 {
+func (x *GetProductsRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *GetProductsRequest) GetPageSize() int32 {
 	if x != nil {
-		return x.Limit
+		return x.PageSize
 	}
 	return 0
 }
 
-func (x *GetProductsRequest) GetOffset() int32 {
+func (x *GetProductsRequest) GetPageToken() string {
 	if x != nil {
-		return x.Offset
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetProductsRequest) GetMinPrice() int64 {
+	if x != nil && x.MinPrice != nil {
+		return *x.MinPrice
 	}
 	return 0
 }
 
-func (x *GetProductsRequest) GetSearch() string {
+func (x *GetProductsRequest) GetMaxPrice() int64 {
+	if x != nil && x.MaxPrice != nil {
+		return *x.MaxPrice
+	}
+	return 0
+}
+
+func (x *GetProductsRequest) GetSellerId() string {
 	if x != nil {
-		return x.Search
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *GetProductsRequest) GetSort() ProductSort {
+	if x != nil {
+		return x.Sort
+	}
+	return ProductSort_PRODUCT_SORT_UNSPECIFIED
+}
+
+func (x *GetProductsRequest) GetCurrencyCode() string {
+	if x != nil {
+		return x.CurrencyCode
+	}
+	return ""
+}
+
+type PriceRangeFacet struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Min   int64                  `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
+	// Unset for the open-ended top range.
+	Max           *int64 `protobuf:"varint,2,opt,name=max,proto3,oneof" json:"max,omitempty"`
+	Count         int64  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceRangeFacet) Reset() {
+	*x = PriceRangeFacet{}
+	mi := &file_product_product_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceRangeFacet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceRangeFacet) ProtoMessage() {}
+
+func (x *PriceRangeFacet) ProtoReflect() protoreflect.Message {
+	mi := &file_product_product_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceRangeFacet.ProtoReflect.Descriptor instead.
+func (*PriceRangeFacet) Descriptor() ([]byte, []int) {
+	return file_product_product_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PriceRangeFacet) GetMin() int64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *PriceRangeFacet) GetMax() int64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+func (x *PriceRangeFacet) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type SellerFacet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SellerId      string                 `protobuf:"bytes,1,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SellerFacet) Reset() {
+	*x = SellerFacet{}
+	mi := &file_product_product_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SellerFacet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SellerFacet) ProtoMessage() {}
+
+func (x *SellerFacet) ProtoReflect() protoreflect.Message {
+	mi := &file_product_product_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SellerFacet.ProtoReflect.Descriptor instead.
+func (*SellerFacet) Descriptor() ([]byte, []int) {
+	return file_product_product_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SellerFacet) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
 	}
 	return ""
 }
 
+func (x *SellerFacet) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// ProductFacets counts every product matching the request filters, not just the current page.
+type ProductFacets struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty unless the request has a currency_code.
+	PriceRanges   []*PriceRangeFacet `protobuf:"bytes,1,rep,name=price_ranges,json=priceRanges,proto3" json:"price_ranges,omitempty"`
+	Sellers       []*SellerFacet     `protobuf:"bytes,2,rep,name=sellers,proto3" json:"sellers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductFacets) Reset() {
+	*x = ProductFacets{}
+	mi := &file_product_product_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductFacets) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductFacets) ProtoMessage() {}
+
+func (x *ProductFacets) ProtoReflect() protoreflect.Message {
+	mi := &file_product_product_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductFacets.ProtoReflect.Descriptor instead.
+func (*ProductFacets) Descriptor() ([]byte, []int) {
+	return file_product_product_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProductFacets) GetPriceRanges() []*PriceRangeFacet {
+	if x != nil {
+		return x.PriceRanges
+	}
+	return nil
+}
+
+func (x *ProductFacets) GetSellers() []*SellerFacet {
+	if x != nil {
+		return x.Sellers
+	}
+	return nil
+}
+
 type GetProductsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Result        []*Product             `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Facets        *ProductFacets         `protobuf:"bytes,3,opt,name=facets,proto3" json:"facets,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetProductsResponse) Reset() {
 	*x = GetProductsResponse{}
-	mi := &file_product_product_proto_msgTypes[3]
+	mi := &file_product_product_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -233,7 +511,7 @@ func (x *GetProductsResponse) String() string {
 func (*GetProductsResponse) ProtoMessage() {}
 
 func (x *GetProductsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_product_product_proto_msgTypes[3]
+	mi := &file_product_product_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -246,7 +524,7 @@ func (x *GetProductsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductsResponse.ProtoReflect.Descriptor instead.
 func (*GetProductsResponse) Descriptor() ([]byte, []int) {
-	return file_product_product_proto_rawDescGZIP(), []int{3}
+	return file_product_product_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetProductsResponse) GetResult() []*Product {
@@ -256,19 +534,34 @@ func (x *GetProductsResponse) GetResult() []*Product {
 	return nil
 }
 
+func (x *GetProductsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *GetProductsResponse) GetFacets() *ProductFacets {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
 type StoreProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         int32                  `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	Price         *common.Money          `protobuf:"bytes,6,opt,name=price,proto3" json:"price,omitempty"`
+	Stock         int64                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *StoreProductRequest) Reset() {
 	*x = StoreProductRequest{}
-	mi := &file_product_product_proto_msgTypes[4]
+	mi := &file_product_product_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -280,7 +573,7 @@ func (x *StoreProductRequest) String() string {
 func (*StoreProductRequest) ProtoMessage() {}
 
 func (x *StoreProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_product_product_proto_msgTypes[4]
+	mi := &file_product_product_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -293,7 +586,7 @@ func (x *StoreProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StoreProductRequest.ProtoReflect.Descriptor instead.
 func (*StoreProductRequest) Descriptor() ([]byte, []int) {
-	return file_product_product_proto_rawDescGZIP(), []int{4}
+	return file_product_product_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *StoreProductRequest) GetId() string {
@@ -317,44 +610,338 @@ func (x *StoreProductRequest) GetDescription() string {
 	return ""
 }
 
-func (x *StoreProductRequest) GetPrice() int32 {
+func (x *StoreProductRequest) GetPrice() *common.Money {
 	if x != nil {
 		return x.Price
 	}
+	return nil
+}
+
+func (x *StoreProductRequest) GetStock() int64 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+type UpdateProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProductRequest) Reset() {
+	*x = UpdateProductRequest{}
+	mi := &file_product_product_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductRequest) ProtoMessage() {}
+
+func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_product_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
+	return file_product_product_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateProductRequest) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *UpdateProductRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type DeleteProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProductRequest) Reset() {
+	*x = DeleteProductRequest{}
+	mi := &file_product_product_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProductRequest) ProtoMessage() {}
+
+func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_product_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProductRequest.ProtoReflect.Descriptor instead.
+func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
+	return file_product_product_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type StockItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockItem) Reset() {
+	*x = StockItem{}
+	mi := &file_product_product_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockItem) ProtoMessage() {}
+
+func (x *StockItem) ProtoReflect() protoreflect.Message {
+	mi := &file_product_product_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockItem.ProtoReflect.Descriptor instead.
+func (*StockItem) Descriptor() ([]byte, []int) {
+	return file_product_product_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StockItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *StockItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
 	return 0
 }
 
+type ReserveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*StockItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_product_product_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_product_product_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_product_product_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReserveStockRequest) GetItems() []*StockItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type StockReservation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId string                 `protobuf:"bytes,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockReservation) Reset() {
+	*x = StockReservation{}
+	mi := &file_product_product_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockReservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockReservation) ProtoMessage() {}
+
+func (x *StockReservation) ProtoReflect() protoreflect.Message {
+	mi := &file_product_product_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockReservation.ProtoReflect.Descriptor instead.
+func (*StockReservation) Descriptor() ([]byte, []int) {
+	return file_product_product_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StockReservation) GetReservationId() string {
+	if x != nil {
+		return x.ReservationId
+	}
+	return ""
+}
+
 var File_product_product_proto protoreflect.FileDescriptor
 
 const file_product_product_proto_rawDesc = "" +
 	"\n" +
-	"\x15product/product.proto\x12\aproduct\x1a\x0fuser/user.proto\x1a\x12common/types.proto\x1a\x1cgoogle/api/annotations.proto\"\x89\x01\n" +
+	"\x15product/product.proto\x12\aproduct\x1a\x0fuser/user.proto\x1a\x12common/types.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\"\xb4\x01\n" +
 	"\aProduct\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x05R\x05price\x12\"\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12#\n" +
+	"\x05price\x18\a \x01(\v2\r.common.MoneyR\x05price\x12\"\n" +
 	"\x06seller\x18\x05 \x01(\v2\n" +
-	".user.UserR\x06seller\"#\n" +
+	".user.UserR\x06seller\x12\x14\n" +
+	"\x05stock\x18\x06 \x01(\x03R\x05stockJ\x04\b\x04\x10\x05\"#\n" +
 	"\x11GetProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"l\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xe1\x02\n" +
 	"\x12GetProductsRequest\x12\x10\n" +
-	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x16\n" +
-	"\x06search\x18\x04 \x01(\tR\x06search\"?\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x16\n" +
+	"\x06search\x18\x04 \x01(\tR\x06search\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\x12 \n" +
+	"\tmin_price\x18\a \x01(\x03H\x00R\bminPrice\x88\x01\x01\x12 \n" +
+	"\tmax_price\x18\b \x01(\x03H\x01R\bmaxPrice\x88\x01\x01\x12\x1b\n" +
+	"\tseller_id\x18\t \x01(\tR\bsellerId\x12(\n" +
+	"\x04sort\x18\n" +
+	" \x01(\x0e2\x14.product.ProductSortR\x04sort\x12#\n" +
+	"\rcurrency_code\x18\v \x01(\tR\fcurrencyCodeB\f\n" +
+	"\n" +
+	"_min_priceB\f\n" +
+	"\n" +
+	"_max_priceJ\x04\b\x02\x10\x03J\x04\b\x03\x10\x04R\x05limitR\x06offset\"X\n" +
+	"\x0fPriceRangeFacet\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\x03R\x03min\x12\x15\n" +
+	"\x03max\x18\x02 \x01(\x03H\x00R\x03max\x88\x01\x01\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05countB\x06\n" +
+	"\x04_max\"@\n" +
+	"\vSellerFacet\x12\x1b\n" +
+	"\tseller_id\x18\x01 \x01(\tR\bsellerId\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"|\n" +
+	"\rProductFacets\x12;\n" +
+	"\fprice_ranges\x18\x01 \x03(\v2\x18.product.PriceRangeFacetR\vpriceRanges\x12.\n" +
+	"\asellers\x18\x02 \x03(\v2\x14.product.SellerFacetR\asellers\"\x97\x01\n" +
 	"\x13GetProductsResponse\x12(\n" +
-	"\x06result\x18\x01 \x03(\v2\x10.product.ProductR\x06result\"q\n" +
+	"\x06result\x18\x01 \x03(\v2\x10.product.ProductR\x06result\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12.\n" +
+	"\x06facets\x18\x03 \x01(\v2\x16.product.ProductFacetsR\x06facets\"\x9c\x01\n" +
 	"\x13StoreProductRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x05R\x05price2\xa0\x02\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12#\n" +
+	"\x05price\x18\x06 \x01(\v2\r.common.MoneyR\x05price\x12\x14\n" +
+	"\x05stock\x18\x05 \x01(\x03R\x05stockJ\x04\b\x04\x10\x05\"\x7f\n" +
+	"\x14UpdateProductRequest\x12*\n" +
+	"\aproduct\x18\x01 \x01(\v2\x10.product.ProductR\aproduct\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"&\n" +
+	"\x14DeleteProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"F\n" +
+	"\tStockItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\"?\n" +
+	"\x13ReserveStockRequest\x12(\n" +
+	"\x05items\x18\x01 \x03(\v2\x12.product.StockItemR\x05items\"9\n" +
+	"\x10StockReservation\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\tR\rreservationId*\x99\x01\n" +
+	"\vProductSort\x12\x1c\n" +
+	"\x18PRODUCT_SORT_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16PRODUCT_SORT_RELEVANCE\x10\x01\x12\x17\n" +
+	"\x13PRODUCT_SORT_NEWEST\x10\x02\x12\x1a\n" +
+	"\x16PRODUCT_SORT_PRICE_ASC\x10\x03\x12\x1b\n" +
+	"\x17PRODUCT_SORT_PRICE_DESC\x10\x042\xc5\x05\n" +
 	"\x0eProductService\x12U\n" +
 	"\n" +
 	"GetProduct\x12\x1a.product.GetProductRequest\x1a\x10.product.Product\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/products/{id}\x12^\n" +
 	"\vGetProducts\x12\x1b.product.GetProductsRequest\x1a\x1c.product.GetProductsResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/products\x12W\n" +
-	"\fStoreProduct\x12\x1c.product.StoreProductRequest\x1a\x10.product.Product\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/productsB;Z9github.com/situmorangbastian/skyros/proto/product;productb\x06proto3"
+	"\fStoreProduct\x12\x1c.product.StoreProductRequest\x1a\x10.product.Product\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/products\x12l\n" +
+	"\rUpdateProduct\x12\x1d.product.UpdateProductRequest\x1a\x10.product.Product\"*\x82\xd3\xe4\x93\x02$:\aproduct2\x19/v1/products/{product.id}\x12a\n" +
+	"\rDeleteProduct\x12\x1d.product.DeleteProductRequest\x1a\x16.google.protobuf.Empty\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/v1/products/{id}\x12I\n" +
+	"\fReserveStock\x12\x1c.product.ReserveStockRequest\x1a\x19.product.StockReservation\"\x00\x12B\n" +
+	"\vCommitStock\x12\x19.product.StockReservation\x1a\x16.google.protobuf.Empty\"\x00\x12C\n" +
+	"\fReleaseStock\x12\x19.product.StockReservation\x1a\x16.google.protobuf.Empty\"\x00B;Z9github.com/situmorangbastian/skyros/proto/product;productb\x06proto3"
 
 var (
 	file_product_product_proto_rawDescOnce sync.Once
@@ -368,29 +955,61 @@ func file_product_product_proto_rawDescGZIP() []byte {
 	return file_product_product_proto_rawDescData
 }
 
-var file_product_product_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_product_product_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_product_product_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_product_product_proto_goTypes = []any{
-	(*Product)(nil),             // 0: product.Product
-	(*GetProductRequest)(nil),   // 1: product.GetProductRequest
-	(*GetProductsRequest)(nil),  // 2: product.GetProductsRequest
-	(*GetProductsResponse)(nil), // 3: product.GetProductsResponse
-	(*StoreProductRequest)(nil), // 4: product.StoreProductRequest
-	(*user.User)(nil),           // 5: user.User
+	(ProductSort)(0),              // 0: product.ProductSort
+	(*Product)(nil),               // 1: product.Product
+	(*GetProductRequest)(nil),     // 2: product.GetProductRequest
+	(*GetProductsRequest)(nil),    // 3: product.GetProductsRequest
+	(*PriceRangeFacet)(nil),       // 4: product.PriceRangeFacet
+	(*SellerFacet)(nil),           // 5: product.SellerFacet
+	(*ProductFacets)(nil),         // 6: product.ProductFacets
+	(*GetProductsResponse)(nil),   // 7: product.GetProductsResponse
+	(*StoreProductRequest)(nil),   // 8: product.StoreProductRequest
+	(*UpdateProductRequest)(nil),  // 9: product.UpdateProductRequest
+	(*DeleteProductRequest)(nil),  // 10: product.DeleteProductRequest
+	(*StockItem)(nil),             // 11: product.StockItem
+	(*ReserveStockRequest)(nil),   // 12: product.ReserveStockRequest
+	(*StockReservation)(nil),      // 13: product.StockReservation
+	(*common.Money)(nil),          // 14: common.Money
+	(*user.User)(nil),             // 15: user.User
+	(*fieldmaskpb.FieldMask)(nil), // 16: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),         // 17: google.protobuf.Empty
 }
 var file_product_product_proto_depIdxs = []int32{
-	5, // 0: product.Product.seller:type_name -> user.User
-	0, // 1: product.GetProductsResponse.result:type_name -> product.Product
-	1, // 2: product.ProductService.GetProduct:input_type -> product.GetProductRequest
-	2, // 3: product.ProductService.GetProducts:input_type -> product.GetProductsRequest
-	4, // 4: product.ProductService.StoreProduct:input_type -> product.StoreProductRequest
-	0, // 5: product.ProductService.GetProduct:output_type -> product.Product
-	3, // 6: product.ProductService.GetProducts:output_type -> product.GetProductsResponse
-	0, // 7: product.ProductService.StoreProduct:output_type -> product.Product
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	14, // 0: product.Product.price:type_name -> common.Money
+	15, // 1: product.Product.seller:type_name -> user.User
+	0,  // 2: product.GetProductsRequest.sort:type_name -> product.ProductSort
+	4,  // 3: product.ProductFacets.price_ranges:type_name -> product.PriceRangeFacet
+	5,  // 4: product.ProductFacets.sellers:type_name -> product.SellerFacet
+	1,  // 5: product.GetProductsResponse.result:type_name -> product.Product
+	6,  // 6: product.GetProductsResponse.facets:type_name -> product.ProductFacets
+	14, // 7: product.StoreProductRequest.price:type_name -> common.Money
+	1,  // 8: product.UpdateProductRequest.product:type_name -> product.Product
+	16, // 9: product.UpdateProductRequest.update_mask:type_name -> google.protobuf.FieldMask
+	11, // 10: product.ReserveStockRequest.items:type_name -> product.StockItem
+	2,  // 11: product.ProductService.GetProduct:input_type -> product.GetProductRequest
+	3,  // 12: product.ProductService.GetProducts:input_type -> product.GetProductsRequest
+	8,  // 13: product.ProductService.StoreProduct:input_type -> product.StoreProductRequest
+	9,  // 14: product.ProductService.UpdateProduct:input_type -> product.UpdateProductRequest
+	10, // 15: product.ProductService.DeleteProduct:input_type -> product.DeleteProductRequest
+	12, // 16: product.ProductService.ReserveStock:input_type -> product.ReserveStockRequest
+	13, // 17: product.ProductService.CommitStock:input_type -> product.StockReservation
+	13, // 18: product.ProductService.ReleaseStock:input_type -> product.StockReservation
+	1,  // 19: product.ProductService.GetProduct:output_type -> product.Product
+	7,  // 20: product.ProductService.GetProducts:output_type -> product.GetProductsResponse
+	1,  // 21: product.ProductService.StoreProduct:output_type -> product.Product
+	1,  // 22: product.ProductService.UpdateProduct:output_type -> product.Product
+	17, // 23: product.ProductService.DeleteProduct:output_type -> google.protobuf.Empty
+	13, // 24: product.ProductService.ReserveStock:output_type -> product.StockReservation
+	17, // 25: product.ProductService.CommitStock:output_type -> google.protobuf.Empty
+	17, // 26: product.ProductService.ReleaseStock:output_type -> google.protobuf.Empty
+	19, // [19:27] is the sub-list for method output_type
+	11, // [11:19] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_product_product_proto_init() }
@@ -398,18 +1017,21 @@ func file_product_product_proto_init() {
 	if File_product_product_proto != nil {
 		return
 	}
+	file_product_product_proto_msgTypes[2].OneofWrappers = []any{}
+	file_product_product_proto_msgTypes[3].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_product_product_proto_rawDesc), len(file_product_product_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      1,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_product_product_proto_goTypes,
 		DependencyIndexes: file_product_product_proto_depIdxs,
+		EnumInfos:         file_product_product_proto_enumTypes,
 		MessageInfos:      file_product_product_proto_msgTypes,
 	}.Build()
 	File_product_product_proto = out.File
@@ -0,0 +1,530 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: promotion/promotion.proto
+
+package promotion
+
+import (
+	common "github.com/situmorangbastian/skyros/proto/common"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DiscountType int32
+
+const (
+	DiscountType_DISCOUNT_TYPE_UNSPECIFIED DiscountType = 0
+	// percent_off of the order subtotal.
+	DiscountType_DISCOUNT_TYPE_PERCENTAGE DiscountType = 1
+	// amount_off, capped at the order subtotal.
+	DiscountType_DISCOUNT_TYPE_FIXED DiscountType = 2
+)
+
+// Enum value maps for DiscountType.
+var (
+	DiscountType_name = map[int32]string{
+		0: "DISCOUNT_TYPE_UNSPECIFIED",
+		1: "DISCOUNT_TYPE_PERCENTAGE",
+		2: "DISCOUNT_TYPE_FIXED",
+	}
+	DiscountType_value = map[string]int32{
+		"DISCOUNT_TYPE_UNSPECIFIED": 0,
+		"DISCOUNT_TYPE_PERCENTAGE":  1,
+		"DISCOUNT_TYPE_FIXED":       2,
+	}
+)
+
+func (x DiscountType) Enum() *DiscountType {
+	p := new(DiscountType)
+	*p = x
+	return p
+}
+
+func (x DiscountType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DiscountType) Descriptor() protoreflect.EnumDescriptor {
+	return file_promotion_promotion_proto_enumTypes[0].Descriptor()
+}
+
+func (DiscountType) Type() protoreflect.EnumType {
+	return &file_promotion_promotion_proto_enumTypes[0]
+}
+
+func (x DiscountType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DiscountType.Descriptor instead.
+func (DiscountType) EnumDescriptor() ([]byte, []int) {
+	return file_promotion_promotion_proto_rawDescGZIP(), []int{0}
+}
+
+// Coupon is a discount code defined by a seller. It applies to the seller's order
+// within a checkout.
+type Coupon struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code         string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	SellerId     string                 `protobuf:"bytes,3,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	DiscountType DiscountType           `protobuf:"varint,4,opt,name=discount_type,json=discountType,proto3,enum=promotion.DiscountType" json:"discount_type,omitempty"`
+	PercentOff   int32                  `protobuf:"varint,5,opt,name=percent_off,json=percentOff,proto3" json:"percent_off,omitempty"`
+	AmountOff    *common.Money          `protobuf:"bytes,6,opt,name=amount_off,json=amountOff,proto3" json:"amount_off,omitempty"`
+	// Unset when the coupon has no minimum.
+	MinOrderValue *common.Money          `protobuf:"bytes,7,opt,name=min_order_value,json=minOrderValue,proto3" json:"min_order_value,omitempty"`
+	StartsAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	// Unset when the coupon never expires.
+	EndsAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	// 0 means unlimited.
+	MaxRedemptions int64 `protobuf:"varint,10,opt,name=max_redemptions,json=maxRedemptions,proto3" json:"max_redemptions,omitempty"`
+	// 0 means unlimited.
+	MaxRedemptionsPerBuyer int64                  `protobuf:"varint,11,opt,name=max_redemptions_per_buyer,json=maxRedemptionsPerBuyer,proto3" json:"max_redemptions_per_buyer,omitempty"`
+	Redemptions            int64                  `protobuf:"varint,12,opt,name=redemptions,proto3" json:"redemptions,omitempty"`
+	CreatedAt              *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *Coupon) Reset() {
+	*x = Coupon{}
+	mi := &file_promotion_promotion_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Coupon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coupon) ProtoMessage() {}
+
+func (x *Coupon) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_promotion_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coupon.ProtoReflect.Descriptor instead.
+func (*Coupon) Descriptor() ([]byte, []int) {
+	return file_promotion_promotion_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Coupon) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Coupon) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Coupon) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *Coupon) GetDiscountType() DiscountType {
+	if x != nil {
+		return x.DiscountType
+	}
+	return DiscountType_DISCOUNT_TYPE_UNSPECIFIED
+}
+
+func (x *Coupon) GetPercentOff() int32 {
+	if x != nil {
+		return x.PercentOff
+	}
+	return 0
+}
+
+func (x *Coupon) GetAmountOff() *common.Money {
+	if x != nil {
+		return x.AmountOff
+	}
+	return nil
+}
+
+func (x *Coupon) GetMinOrderValue() *common.Money {
+	if x != nil {
+		return x.MinOrderValue
+	}
+	return nil
+}
+
+func (x *Coupon) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *Coupon) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *Coupon) GetMaxRedemptions() int64 {
+	if x != nil {
+		return x.MaxRedemptions
+	}
+	return 0
+}
+
+func (x *Coupon) GetMaxRedemptionsPerBuyer() int64 {
+	if x != nil {
+		return x.MaxRedemptionsPerBuyer
+	}
+	return 0
+}
+
+func (x *Coupon) GetRedemptions() int64 {
+	if x != nil {
+		return x.Redemptions
+	}
+	return 0
+}
+
+func (x *Coupon) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateCouponRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	DiscountType  DiscountType           `protobuf:"varint,2,opt,name=discount_type,json=discountType,proto3,enum=promotion.DiscountType" json:"discount_type,omitempty"`
+	PercentOff    int32                  `protobuf:"varint,3,opt,name=percent_off,json=percentOff,proto3" json:"percent_off,omitempty"`
+	AmountOff     *common.Money          `protobuf:"bytes,4,opt,name=amount_off,json=amountOff,proto3" json:"amount_off,omitempty"`
+	MinOrderValue *common.Money          `protobuf:"bytes,5,opt,name=min_order_value,json=minOrderValue,proto3" json:"min_order_value,omitempty"`
+	// Defaults to now.
+	StartsAt               *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt                 *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	MaxRedemptions         int64                  `protobuf:"varint,8,opt,name=max_redemptions,json=maxRedemptions,proto3" json:"max_redemptions,omitempty"`
+	MaxRedemptionsPerBuyer int64                  `protobuf:"varint,9,opt,name=max_redemptions_per_buyer,json=maxRedemptionsPerBuyer,proto3" json:"max_redemptions_per_buyer,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *CreateCouponRequest) Reset() {
+	*x = CreateCouponRequest{}
+	mi := &file_promotion_promotion_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCouponRequest) ProtoMessage() {}
+
+func (x *CreateCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_promotion_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCouponRequest.ProtoReflect.Descriptor instead.
+func (*CreateCouponRequest) Descriptor() ([]byte, []int) {
+	return file_promotion_promotion_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateCouponRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *CreateCouponRequest) GetDiscountType() DiscountType {
+	if x != nil {
+		return x.DiscountType
+	}
+	return DiscountType_DISCOUNT_TYPE_UNSPECIFIED
+}
+
+func (x *CreateCouponRequest) GetPercentOff() int32 {
+	if x != nil {
+		return x.PercentOff
+	}
+	return 0
+}
+
+func (x *CreateCouponRequest) GetAmountOff() *common.Money {
+	if x != nil {
+		return x.AmountOff
+	}
+	return nil
+}
+
+func (x *CreateCouponRequest) GetMinOrderValue() *common.Money {
+	if x != nil {
+		return x.MinOrderValue
+	}
+	return nil
+}
+
+func (x *CreateCouponRequest) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *CreateCouponRequest) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *CreateCouponRequest) GetMaxRedemptions() int64 {
+	if x != nil {
+		return x.MaxRedemptions
+	}
+	return 0
+}
+
+func (x *CreateCouponRequest) GetMaxRedemptionsPerBuyer() int64 {
+	if x != nil {
+		return x.MaxRedemptionsPerBuyer
+	}
+	return 0
+}
+
+type GetCouponsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCouponsRequest) Reset() {
+	*x = GetCouponsRequest{}
+	mi := &file_promotion_promotion_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCouponsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCouponsRequest) ProtoMessage() {}
+
+func (x *GetCouponsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_promotion_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCouponsRequest.ProtoReflect.Descriptor instead.
+func (*GetCouponsRequest) Descriptor() ([]byte, []int) {
+	return file_promotion_promotion_proto_rawDescGZIP(), []int{2}
+}
+
+type GetCouponsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        []*Coupon              `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCouponsResponse) Reset() {
+	*x = GetCouponsResponse{}
+	mi := &file_promotion_promotion_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCouponsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCouponsResponse) ProtoMessage() {}
+
+func (x *GetCouponsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_promotion_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCouponsResponse.ProtoReflect.Descriptor instead.
+func (*GetCouponsResponse) Descriptor() ([]byte, []int) {
+	return file_promotion_promotion_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetCouponsResponse) GetResult() []*Coupon {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+var File_promotion_promotion_proto protoreflect.FileDescriptor
+
+const file_promotion_promotion_proto_rawDesc = "" +
+	"\n" +
+	"\x19promotion/promotion.proto\x12\tpromotion\x1a\x12common/types.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbc\x04\n" +
+	"\x06Coupon\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x1b\n" +
+	"\tseller_id\x18\x03 \x01(\tR\bsellerId\x12<\n" +
+	"\rdiscount_type\x18\x04 \x01(\x0e2\x17.promotion.DiscountTypeR\fdiscountType\x12\x1f\n" +
+	"\vpercent_off\x18\x05 \x01(\x05R\n" +
+	"percentOff\x12,\n" +
+	"\n" +
+	"amount_off\x18\x06 \x01(\v2\r.common.MoneyR\tamountOff\x125\n" +
+	"\x0fmin_order_value\x18\a \x01(\v2\r.common.MoneyR\rminOrderValue\x127\n" +
+	"\tstarts_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x12'\n" +
+	"\x0fmax_redemptions\x18\n" +
+	" \x01(\x03R\x0emaxRedemptions\x129\n" +
+	"\x19max_redemptions_per_buyer\x18\v \x01(\x03R\x16maxRedemptionsPerBuyer\x12 \n" +
+	"\vredemptions\x18\f \x01(\x03R\vredemptions\x129\n" +
+	"\n" +
+	"created_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xbf\x03\n" +
+	"\x13CreateCouponRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12<\n" +
+	"\rdiscount_type\x18\x02 \x01(\x0e2\x17.promotion.DiscountTypeR\fdiscountType\x12\x1f\n" +
+	"\vpercent_off\x18\x03 \x01(\x05R\n" +
+	"percentOff\x12,\n" +
+	"\n" +
+	"amount_off\x18\x04 \x01(\v2\r.common.MoneyR\tamountOff\x125\n" +
+	"\x0fmin_order_value\x18\x05 \x01(\v2\r.common.MoneyR\rminOrderValue\x127\n" +
+	"\tstarts_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x12'\n" +
+	"\x0fmax_redemptions\x18\b \x01(\x03R\x0emaxRedemptions\x129\n" +
+	"\x19max_redemptions_per_buyer\x18\t \x01(\x03R\x16maxRedemptionsPerBuyer\"\x13\n" +
+	"\x11GetCouponsRequest\"?\n" +
+	"\x12GetCouponsResponse\x12)\n" +
+	"\x06result\x18\x01 \x03(\v2\x11.promotion.CouponR\x06result*d\n" +
+	"\fDiscountType\x12\x1d\n" +
+	"\x19DISCOUNT_TYPE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18DISCOUNT_TYPE_PERCENTAGE\x10\x01\x12\x17\n" +
+	"\x13DISCOUNT_TYPE_FIXED\x10\x022\xcd\x01\n" +
+	"\x10PromotionService\x12Y\n" +
+	"\fCreateCoupon\x12\x1e.promotion.CreateCouponRequest\x1a\x11.promotion.Coupon\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/coupons\x12^\n" +
+	"\n" +
+	"GetCoupons\x12\x1c.promotion.GetCouponsRequest\x1a\x1d.promotion.GetCouponsResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/couponsB?Z=github.com/situmorangbastian/skyros/proto/promotion;promotionb\x06proto3"
+
+var (
+	file_promotion_promotion_proto_rawDescOnce sync.Once
+	file_promotion_promotion_proto_rawDescData []byte
+)
+
+func file_promotion_promotion_proto_rawDescGZIP() []byte {
+	file_promotion_promotion_proto_rawDescOnce.Do(func() {
+		file_promotion_promotion_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_promotion_promotion_proto_rawDesc), len(file_promotion_promotion_proto_rawDesc)))
+	})
+	return file_promotion_promotion_proto_rawDescData
+}
+
+var file_promotion_promotion_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_promotion_promotion_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_promotion_promotion_proto_goTypes = []any{
+	(DiscountType)(0),             // 0: promotion.DiscountType
+	(*Coupon)(nil),                // 1: promotion.Coupon
+	(*CreateCouponRequest)(nil),   // 2: promotion.CreateCouponRequest
+	(*GetCouponsRequest)(nil),     // 3: promotion.GetCouponsRequest
+	(*GetCouponsResponse)(nil),    // 4: promotion.GetCouponsResponse
+	(*common.Money)(nil),          // 5: common.Money
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+}
+var file_promotion_promotion_proto_depIdxs = []int32{
+	0,  // 0: promotion.Coupon.discount_type:type_name -> promotion.DiscountType
+	5,  // 1: promotion.Coupon.amount_off:type_name -> common.Money
+	5,  // 2: promotion.Coupon.min_order_value:type_name -> common.Money
+	6,  // 3: promotion.Coupon.starts_at:type_name -> google.protobuf.Timestamp
+	6,  // 4: promotion.Coupon.ends_at:type_name -> google.protobuf.Timestamp
+	6,  // 5: promotion.Coupon.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 6: promotion.CreateCouponRequest.discount_type:type_name -> promotion.DiscountType
+	5,  // 7: promotion.CreateCouponRequest.amount_off:type_name -> common.Money
+	5,  // 8: promotion.CreateCouponRequest.min_order_value:type_name -> common.Money
+	6,  // 9: promotion.CreateCouponRequest.starts_at:type_name -> google.protobuf.Timestamp
+	6,  // 10: promotion.CreateCouponRequest.ends_at:type_name -> google.protobuf.Timestamp
+	1,  // 11: promotion.GetCouponsResponse.result:type_name -> promotion.Coupon
+	2,  // 12: promotion.PromotionService.CreateCoupon:input_type -> promotion.CreateCouponRequest
+	3,  // 13: promotion.PromotionService.GetCoupons:input_type -> promotion.GetCouponsRequest
+	1,  // 14: promotion.PromotionService.CreateCoupon:output_type -> promotion.Coupon
+	4,  // 15: promotion.PromotionService.GetCoupons:output_type -> promotion.GetCouponsResponse
+	14, // [14:16] is the sub-list for method output_type
+	12, // [12:14] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_promotion_promotion_proto_init() }
+func file_promotion_promotion_proto_init() {
+	if File_promotion_promotion_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_promotion_promotion_proto_rawDesc), len(file_promotion_promotion_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_promotion_promotion_proto_goTypes,
+		DependencyIndexes: file_promotion_promotion_proto_depIdxs,
+		EnumInfos:         file_promotion_promotion_proto_enumTypes,
+		MessageInfos:      file_promotion_promotion_proto_msgTypes,
+	}.Build()
+	File_promotion_promotion_proto = out.File
+	file_promotion_promotion_proto_goTypes = nil
+	file_promotion_promotion_proto_depIdxs = nil
+}
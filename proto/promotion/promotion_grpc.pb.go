@@ -0,0 +1,157 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: promotion/promotion.proto
+
+package promotion
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PromotionService_CreateCoupon_FullMethodName = "/promotion.PromotionService/CreateCoupon"
+	PromotionService_GetCoupons_FullMethodName   = "/promotion.PromotionService/GetCoupons"
+)
+
+// PromotionServiceClient is the client API for PromotionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PromotionServiceClient interface {
+	CreateCoupon(ctx context.Context, in *CreateCouponRequest, opts ...grpc.CallOption) (*Coupon, error)
+	GetCoupons(ctx context.Context, in *GetCouponsRequest, opts ...grpc.CallOption) (*GetCouponsResponse, error)
+}
+
+type promotionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPromotionServiceClient(cc grpc.ClientConnInterface) PromotionServiceClient {
+	return &promotionServiceClient{cc}
+}
+
+func (c *promotionServiceClient) CreateCoupon(ctx context.Context, in *CreateCouponRequest, opts ...grpc.CallOption) (*Coupon, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Coupon)
+	err := c.cc.Invoke(ctx, PromotionService_CreateCoupon_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *promotionServiceClient) GetCoupons(ctx context.Context, in *GetCouponsRequest, opts ...grpc.CallOption) (*GetCouponsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCouponsResponse)
+	err := c.cc.Invoke(ctx, PromotionService_GetCoupons_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PromotionServiceServer is the server API for PromotionService service.
+// All implementations should embed UnimplementedPromotionServiceServer
+// for forward compatibility.
+type PromotionServiceServer interface {
+	CreateCoupon(context.Context, *CreateCouponRequest) (*Coupon, error)
+	GetCoupons(context.Context, *GetCouponsRequest) (*GetCouponsResponse, error)
+}
+
+// UnimplementedPromotionServiceServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPromotionServiceServer struct{}
+
+func (UnimplementedPromotionServiceServer) CreateCoupon(context.Context, *CreateCouponRequest) (*Coupon, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCoupon not implemented")
+}
+func (UnimplementedPromotionServiceServer) GetCoupons(context.Context, *GetCouponsRequest) (*GetCouponsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCoupons not implemented")
+}
+func (UnimplementedPromotionServiceServer) testEmbeddedByValue() {}
+
+// UnsafePromotionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PromotionServiceServer will
+// result in compilation errors.
+type UnsafePromotionServiceServer interface {
+	mustEmbedUnimplementedPromotionServiceServer()
+}
+
+func RegisterPromotionServiceServer(s grpc.ServiceRegistrar, srv PromotionServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPromotionServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PromotionService_ServiceDesc, srv)
+}
+
+func _PromotionService_CreateCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCouponRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PromotionServiceServer).CreateCoupon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PromotionService_CreateCoupon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PromotionServiceServer).CreateCoupon(ctx, req.(*CreateCouponRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PromotionService_GetCoupons_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCouponsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PromotionServiceServer).GetCoupons(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PromotionService_GetCoupons_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PromotionServiceServer).GetCoupons(ctx, req.(*GetCouponsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PromotionService_ServiceDesc is the grpc.ServiceDesc for PromotionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PromotionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "promotion.PromotionService",
+	HandlerType: (*PromotionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateCoupon",
+			Handler:    _PromotionService_CreateCoupon_Handler,
+		},
+		{
+			MethodName: "GetCoupons",
+			Handler:    _PromotionService_GetCoupons_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "promotion/promotion.proto",
+}
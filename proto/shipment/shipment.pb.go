@@ -0,0 +1,776 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: shipment/shipment.proto
+
+package shipment
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ShipmentStatus int32
+
+const (
+	ShipmentStatus_SHIPMENT_STATUS_UNSPECIFIED ShipmentStatus = 0
+	// The status of a new shipment; tracking events cannot report it.
+	ShipmentStatus_SHIPMENT_STATUS_LABEL_CREATED    ShipmentStatus = 1
+	ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT       ShipmentStatus = 2
+	ShipmentStatus_SHIPMENT_STATUS_OUT_FOR_DELIVERY ShipmentStatus = 3
+	ShipmentStatus_SHIPMENT_STATUS_DELIVERY_FAILED  ShipmentStatus = 4
+	// Final.
+	ShipmentStatus_SHIPMENT_STATUS_DELIVERED ShipmentStatus = 5
+	// Final.
+	ShipmentStatus_SHIPMENT_STATUS_RETURNED ShipmentStatus = 6
+)
+
+// Enum value maps for ShipmentStatus.
+var (
+	ShipmentStatus_name = map[int32]string{
+		0: "SHIPMENT_STATUS_UNSPECIFIED",
+		1: "SHIPMENT_STATUS_LABEL_CREATED",
+		2: "SHIPMENT_STATUS_IN_TRANSIT",
+		3: "SHIPMENT_STATUS_OUT_FOR_DELIVERY",
+		4: "SHIPMENT_STATUS_DELIVERY_FAILED",
+		5: "SHIPMENT_STATUS_DELIVERED",
+		6: "SHIPMENT_STATUS_RETURNED",
+	}
+	ShipmentStatus_value = map[string]int32{
+		"SHIPMENT_STATUS_UNSPECIFIED":      0,
+		"SHIPMENT_STATUS_LABEL_CREATED":    1,
+		"SHIPMENT_STATUS_IN_TRANSIT":       2,
+		"SHIPMENT_STATUS_OUT_FOR_DELIVERY": 3,
+		"SHIPMENT_STATUS_DELIVERY_FAILED":  4,
+		"SHIPMENT_STATUS_DELIVERED":        5,
+		"SHIPMENT_STATUS_RETURNED":         6,
+	}
+)
+
+func (x ShipmentStatus) Enum() *ShipmentStatus {
+	p := new(ShipmentStatus)
+	*p = x
+	return p
+}
+
+func (x ShipmentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ShipmentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_shipment_shipment_proto_enumTypes[0].Descriptor()
+}
+
+func (ShipmentStatus) Type() protoreflect.EnumType {
+	return &file_shipment_shipment_proto_enumTypes[0]
+}
+
+func (x ShipmentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ShipmentStatus.Descriptor instead.
+func (ShipmentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_shipment_shipment_proto_rawDescGZIP(), []int{0}
+}
+
+type ShipmentItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShipmentItem) Reset() {
+	*x = ShipmentItem{}
+	mi := &file_shipment_shipment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShipmentItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShipmentItem) ProtoMessage() {}
+
+func (x *ShipmentItem) ProtoReflect() protoreflect.Message {
+	mi := &file_shipment_shipment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShipmentItem.ProtoReflect.Descriptor instead.
+func (*ShipmentItem) Descriptor() ([]byte, []int) {
+	return file_shipment_shipment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ShipmentItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ShipmentItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type TrackingEvent struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Status      ShipmentStatus         `protobuf:"varint,1,opt,name=status,proto3,enum=shipment.ShipmentStatus" json:"status,omitempty"`
+	Location    string                 `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// When the carrier recorded the event.
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackingEvent) Reset() {
+	*x = TrackingEvent{}
+	mi := &file_shipment_shipment_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackingEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackingEvent) ProtoMessage() {}
+
+func (x *TrackingEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_shipment_shipment_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackingEvent.ProtoReflect.Descriptor instead.
+func (*TrackingEvent) Descriptor() ([]byte, []int) {
+	return file_shipment_shipment_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TrackingEvent) GetStatus() ShipmentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ShipmentStatus_SHIPMENT_STATUS_UNSPECIFIED
+}
+
+func (x *TrackingEvent) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *TrackingEvent) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TrackingEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *TrackingEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// Shipment is one package of an order. An order can be sent in several shipments.
+type Shipment struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId            string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Carrier            string                 `protobuf:"bytes,3,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber     string                 `protobuf:"bytes,4,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	SourceAddress      string                 `protobuf:"bytes,5,opt,name=source_address,json=sourceAddress,proto3" json:"source_address,omitempty"`
+	DestinationAddress string                 `protobuf:"bytes,6,opt,name=destination_address,json=destinationAddress,proto3" json:"destination_address,omitempty"`
+	Status             ShipmentStatus         `protobuf:"varint,7,opt,name=status,proto3,enum=shipment.ShipmentStatus" json:"status,omitempty"`
+	Items              []*ShipmentItem        `protobuf:"bytes,8,rep,name=items,proto3" json:"items,omitempty"`
+	// Oldest first.
+	Events        []*TrackingEvent       `protobuf:"bytes,9,rep,name=events,proto3" json:"events,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Shipment) Reset() {
+	*x = Shipment{}
+	mi := &file_shipment_shipment_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Shipment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shipment) ProtoMessage() {}
+
+func (x *Shipment) ProtoReflect() protoreflect.Message {
+	mi := &file_shipment_shipment_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shipment.ProtoReflect.Descriptor instead.
+func (*Shipment) Descriptor() ([]byte, []int) {
+	return file_shipment_shipment_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Shipment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Shipment) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Shipment) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *Shipment) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *Shipment) GetSourceAddress() string {
+	if x != nil {
+		return x.SourceAddress
+	}
+	return ""
+}
+
+func (x *Shipment) GetDestinationAddress() string {
+	if x != nil {
+		return x.DestinationAddress
+	}
+	return ""
+}
+
+func (x *Shipment) GetStatus() ShipmentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ShipmentStatus_SHIPMENT_STATUS_UNSPECIFIED
+}
+
+func (x *Shipment) GetItems() []*ShipmentItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Shipment) GetEvents() []*TrackingEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *Shipment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Shipment) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreateShipmentRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrderId        string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Carrier        string                 `protobuf:"bytes,2,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,3,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	// Items in the package; every item not shipped yet when empty.
+	Items []*ShipmentItem `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	// Defaults to the order's source address, then to the seller's address.
+	SourceAddress string `protobuf:"bytes,5,opt,name=source_address,json=sourceAddress,proto3" json:"source_address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateShipmentRequest) Reset() {
+	*x = CreateShipmentRequest{}
+	mi := &file_shipment_shipment_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShipmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShipmentRequest) ProtoMessage() {}
+
+func (x *CreateShipmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipment_shipment_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShipmentRequest.ProtoReflect.Descriptor instead.
+func (*CreateShipmentRequest) Descriptor() ([]byte, []int) {
+	return file_shipment_shipment_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateShipmentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CreateShipmentRequest) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *CreateShipmentRequest) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *CreateShipmentRequest) GetItems() []*ShipmentItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CreateShipmentRequest) GetSourceAddress() string {
+	if x != nil {
+		return x.SourceAddress
+	}
+	return ""
+}
+
+type AddTrackingEventRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ShipmentId  string                 `protobuf:"bytes,1,opt,name=shipment_id,json=shipmentId,proto3" json:"shipment_id,omitempty"`
+	Status      ShipmentStatus         `protobuf:"varint,2,opt,name=status,proto3,enum=shipment.ShipmentStatus" json:"status,omitempty"`
+	Location    string                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	// Defaults to now.
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTrackingEventRequest) Reset() {
+	*x = AddTrackingEventRequest{}
+	mi := &file_shipment_shipment_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTrackingEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTrackingEventRequest) ProtoMessage() {}
+
+func (x *AddTrackingEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipment_shipment_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTrackingEventRequest.ProtoReflect.Descriptor instead.
+func (*AddTrackingEventRequest) Descriptor() ([]byte, []int) {
+	return file_shipment_shipment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AddTrackingEventRequest) GetShipmentId() string {
+	if x != nil {
+		return x.ShipmentId
+	}
+	return ""
+}
+
+func (x *AddTrackingEventRequest) GetStatus() ShipmentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ShipmentStatus_SHIPMENT_STATUS_UNSPECIFIED
+}
+
+func (x *AddTrackingEventRequest) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *AddTrackingEventRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *AddTrackingEventRequest) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+type GetShipmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShipmentId    string                 `protobuf:"bytes,1,opt,name=shipment_id,json=shipmentId,proto3" json:"shipment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetShipmentRequest) Reset() {
+	*x = GetShipmentRequest{}
+	mi := &file_shipment_shipment_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetShipmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShipmentRequest) ProtoMessage() {}
+
+func (x *GetShipmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipment_shipment_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShipmentRequest.ProtoReflect.Descriptor instead.
+func (*GetShipmentRequest) Descriptor() ([]byte, []int) {
+	return file_shipment_shipment_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetShipmentRequest) GetShipmentId() string {
+	if x != nil {
+		return x.ShipmentId
+	}
+	return ""
+}
+
+type GetShipmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetShipmentsRequest) Reset() {
+	*x = GetShipmentsRequest{}
+	mi := &file_shipment_shipment_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetShipmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShipmentsRequest) ProtoMessage() {}
+
+func (x *GetShipmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipment_shipment_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShipmentsRequest.ProtoReflect.Descriptor instead.
+func (*GetShipmentsRequest) Descriptor() ([]byte, []int) {
+	return file_shipment_shipment_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetShipmentsRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetShipmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        []*Shipment            `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetShipmentsResponse) Reset() {
+	*x = GetShipmentsResponse{}
+	mi := &file_shipment_shipment_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetShipmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShipmentsResponse) ProtoMessage() {}
+
+func (x *GetShipmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipment_shipment_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShipmentsResponse.ProtoReflect.Descriptor instead.
+func (*GetShipmentsResponse) Descriptor() ([]byte, []int) {
+	return file_shipment_shipment_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetShipmentsResponse) GetResult() []*Shipment {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+var File_shipment_shipment_proto protoreflect.FileDescriptor
+
+const file_shipment_shipment_proto_rawDesc = "" +
+	"\n" +
+	"\x17shipment/shipment.proto\x12\bshipment\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"I\n" +
+	"\fShipmentItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\"\xf7\x01\n" +
+	"\rTrackingEvent\x120\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x18.shipment.ShipmentStatusR\x06status\x12\x1a\n" +
+	"\blocation\x18\x02 \x01(\tR\blocation\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12;\n" +
+	"\voccurred_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xd7\x03\n" +
+	"\bShipment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x18\n" +
+	"\acarrier\x18\x03 \x01(\tR\acarrier\x12'\n" +
+	"\x0ftracking_number\x18\x04 \x01(\tR\x0etrackingNumber\x12%\n" +
+	"\x0esource_address\x18\x05 \x01(\tR\rsourceAddress\x12/\n" +
+	"\x13destination_address\x18\x06 \x01(\tR\x12destinationAddress\x120\n" +
+	"\x06status\x18\a \x01(\x0e2\x18.shipment.ShipmentStatusR\x06status\x12,\n" +
+	"\x05items\x18\b \x03(\v2\x16.shipment.ShipmentItemR\x05items\x12/\n" +
+	"\x06events\x18\t \x03(\v2\x17.shipment.TrackingEventR\x06events\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xca\x01\n" +
+	"\x15CreateShipmentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x18\n" +
+	"\acarrier\x18\x02 \x01(\tR\acarrier\x12'\n" +
+	"\x0ftracking_number\x18\x03 \x01(\tR\x0etrackingNumber\x12,\n" +
+	"\x05items\x18\x04 \x03(\v2\x16.shipment.ShipmentItemR\x05items\x12%\n" +
+	"\x0esource_address\x18\x05 \x01(\tR\rsourceAddress\"\xe7\x01\n" +
+	"\x17AddTrackingEventRequest\x12\x1f\n" +
+	"\vshipment_id\x18\x01 \x01(\tR\n" +
+	"shipmentId\x120\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x18.shipment.ShipmentStatusR\x06status\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12;\n" +
+	"\voccurred_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\"5\n" +
+	"\x12GetShipmentRequest\x12\x1f\n" +
+	"\vshipment_id\x18\x01 \x01(\tR\n" +
+	"shipmentId\"0\n" +
+	"\x13GetShipmentsRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"B\n" +
+	"\x14GetShipmentsResponse\x12*\n" +
+	"\x06result\x18\x01 \x03(\v2\x12.shipment.ShipmentR\x06result*\xfc\x01\n" +
+	"\x0eShipmentStatus\x12\x1f\n" +
+	"\x1bSHIPMENT_STATUS_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dSHIPMENT_STATUS_LABEL_CREATED\x10\x01\x12\x1e\n" +
+	"\x1aSHIPMENT_STATUS_IN_TRANSIT\x10\x02\x12$\n" +
+	" SHIPMENT_STATUS_OUT_FOR_DELIVERY\x10\x03\x12#\n" +
+	"\x1fSHIPMENT_STATUS_DELIVERY_FAILED\x10\x04\x12\x1d\n" +
+	"\x19SHIPMENT_STATUS_DELIVERED\x10\x05\x12\x1c\n" +
+	"\x18SHIPMENT_STATUS_RETURNED\x10\x062\xdc\x03\n" +
+	"\x0fShipmentService\x12q\n" +
+	"\x0eCreateShipment\x12\x1f.shipment.CreateShipmentRequest\x1a\x12.shipment.Shipment\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/orders/{order_id}/shipments\x12v\n" +
+	"\fGetShipments\x12\x1d.shipment.GetShipmentsRequest\x1a\x1e.shipment.GetShipmentsResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/v1/orders/{order_id}/shipments\x12d\n" +
+	"\vGetShipment\x12\x1c.shipment.GetShipmentRequest\x1a\x12.shipment.Shipment\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/v1/shipments/{shipment_id}\x12x\n" +
+	"\x10AddTrackingEvent\x12!.shipment.AddTrackingEventRequest\x1a\x12.shipment.Shipment\"-\x82\xd3\xe4\x93\x02':\x01*\"\"/v1/shipments/{shipment_id}/eventsB=Z;github.com/situmorangbastian/skyros/proto/shipment;shipmentb\x06proto3"
+
+var (
+	file_shipment_shipment_proto_rawDescOnce sync.Once
+	file_shipment_shipment_proto_rawDescData []byte
+)
+
+func file_shipment_shipment_proto_rawDescGZIP() []byte {
+	file_shipment_shipment_proto_rawDescOnce.Do(func() {
+		file_shipment_shipment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_shipment_shipment_proto_rawDesc), len(file_shipment_shipment_proto_rawDesc)))
+	})
+	return file_shipment_shipment_proto_rawDescData
+}
+
+var file_shipment_shipment_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_shipment_shipment_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_shipment_shipment_proto_goTypes = []any{
+	(ShipmentStatus)(0),             // 0: shipment.ShipmentStatus
+	(*ShipmentItem)(nil),            // 1: shipment.ShipmentItem
+	(*TrackingEvent)(nil),           // 2: shipment.TrackingEvent
+	(*Shipment)(nil),                // 3: shipment.Shipment
+	(*CreateShipmentRequest)(nil),   // 4: shipment.CreateShipmentRequest
+	(*AddTrackingEventRequest)(nil), // 5: shipment.AddTrackingEventRequest
+	(*GetShipmentRequest)(nil),      // 6: shipment.GetShipmentRequest
+	(*GetShipmentsRequest)(nil),     // 7: shipment.GetShipmentsRequest
+	(*GetShipmentsResponse)(nil),    // 8: shipment.GetShipmentsResponse
+	(*timestamppb.Timestamp)(nil),   // 9: google.protobuf.Timestamp
+}
+var file_shipment_shipment_proto_depIdxs = []int32{
+	0,  // 0: shipment.TrackingEvent.status:type_name -> shipment.ShipmentStatus
+	9,  // 1: shipment.TrackingEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	9,  // 2: shipment.TrackingEvent.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: shipment.Shipment.status:type_name -> shipment.ShipmentStatus
+	1,  // 4: shipment.Shipment.items:type_name -> shipment.ShipmentItem
+	2,  // 5: shipment.Shipment.events:type_name -> shipment.TrackingEvent
+	9,  // 6: shipment.Shipment.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 7: shipment.Shipment.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 8: shipment.CreateShipmentRequest.items:type_name -> shipment.ShipmentItem
+	0,  // 9: shipment.AddTrackingEventRequest.status:type_name -> shipment.ShipmentStatus
+	9,  // 10: shipment.AddTrackingEventRequest.occurred_at:type_name -> google.protobuf.Timestamp
+	3,  // 11: shipment.GetShipmentsResponse.result:type_name -> shipment.Shipment
+	4,  // 12: shipment.ShipmentService.CreateShipment:input_type -> shipment.CreateShipmentRequest
+	7,  // 13: shipment.ShipmentService.GetShipments:input_type -> shipment.GetShipmentsRequest
+	6,  // 14: shipment.ShipmentService.GetShipment:input_type -> shipment.GetShipmentRequest
+	5,  // 15: shipment.ShipmentService.AddTrackingEvent:input_type -> shipment.AddTrackingEventRequest
+	3,  // 16: shipment.ShipmentService.CreateShipment:output_type -> shipment.Shipment
+	8,  // 17: shipment.ShipmentService.GetShipments:output_type -> shipment.GetShipmentsResponse
+	3,  // 18: shipment.ShipmentService.GetShipment:output_type -> shipment.Shipment
+	3,  // 19: shipment.ShipmentService.AddTrackingEvent:output_type -> shipment.Shipment
+	16, // [16:20] is the sub-list for method output_type
+	12, // [12:16] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_shipment_shipment_proto_init() }
+func file_shipment_shipment_proto_init() {
+	if File_shipment_shipment_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_shipment_shipment_proto_rawDesc), len(file_shipment_shipment_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_shipment_shipment_proto_goTypes,
+		DependencyIndexes: file_shipment_shipment_proto_depIdxs,
+		EnumInfos:         file_shipment_shipment_proto_enumTypes,
+		MessageInfos:      file_shipment_shipment_proto_msgTypes,
+	}.Build()
+	File_shipment_shipment_proto = out.File
+	file_shipment_shipment_proto_goTypes = nil
+	file_shipment_shipment_proto_depIdxs = nil
+}
@@ -11,6 +11,8 @@ type UserType string
 const (
 	UserSellerType UserType = "seller"
 	UserBuyerType  UserType = "buyer"
+	// UserAdminType is not tied to a tenant: admins can act on every seller's and buyer's data.
+	UserAdminType UserType = "admin"
 )
 
 // Claims holds only what other services need to know about an authenticated user.
@@ -21,6 +23,8 @@ type Claims struct {
 	Name    string   `json:"name"`
 	Address string   `json:"address"`
 	Type    UserType `json:"type"`
+	// EmailVerified tells whether the user proved they own Email.
+	EmailVerified bool `json:"email_verified"`
 }
 
 type UserClient interface {
@@ -29,10 +33,11 @@ type UserClient interface {
 
 func ToAuthClaims(u *userpb.User) Claims {
 	return Claims{
-		ID:      u.GetId(),
-		Email:   u.GetEmail(),
-		Name:    u.GetName(),
-		Address: u.GetAddress(),
-		Type:    UserType(u.GetType()),
+		ID:            u.GetId(),
+		Email:         u.GetEmail(),
+		Name:          u.GetName(),
+		Address:       u.GetAddress(),
+		Type:          UserType(u.GetType()),
+		EmailVerified: u.GetEmailVerified(),
 	}
 }
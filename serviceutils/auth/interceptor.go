@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"slices"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,7 +16,10 @@ type contextKey string
 
 const userClaimsKey contextKey = "userClaims"
 
-func AuthInterceptor(secretKey string, userClient UserClient) grpc.UnaryServerInterceptor {
+// AuthInterceptor sets the calling user's claims on the context. An identity forwarded by
+// a trusted gateway is used as is; otherwise requests coming through this service's own
+// grpc-gateway must carry a bearer token, which is validated and resolved against userservice.
+func AuthInterceptor(keys *KeySet, userClient UserClient, identities *IdentityVerifier) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req any,
@@ -27,54 +31,94 @@ func AuthInterceptor(secretKey string, userClient UserClient) grpc.UnaryServerIn
 			return nil, status.Error(codes.Unauthenticated, "missing metadata")
 		}
 
-		if isGRPCGatewayRequest(md) {
-			authHeaders := md.Get("authorization")
-			if len(authHeaders) == 0 || !strings.HasPrefix(authHeaders[0], "Bearer ") {
-				return nil, status.Error(codes.Unauthenticated, "missing or invalid bearer token")
-			}
-
-			tokenStr := strings.TrimPrefix(authHeaders[0], "Bearer ")
-			token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (any, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, status.Errorf(codes.Unauthenticated, "unexpected signing method: %v", token.Header["alg"])
-				}
-				return []byte(secretKey), nil
-			})
-			if err != nil || !token.Valid {
-				return nil, status.Error(codes.Unauthenticated, "invalid token")
-			}
-
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				return nil, status.Error(codes.Unauthenticated, "invalid token claims")
+		if values := md.Get(UserIdentityKey); len(values) > 0 {
+			user, err := identities.Verify(values[0], grpcServiceName(info.FullMethod))
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, "invalid user identity")
 			}
+			return handler(WithUserClaims(ctx, user), req)
+		}
 
-			userID, ok := claims["id"].(string)
-			if !ok || userID == "" {
-				return nil, status.Error(codes.Unauthenticated, "invalid token claims")
+		if isGRPCGatewayRequest(md) {
+			var authorization string
+			if authHeaders := md.Get("authorization"); len(authHeaders) > 0 {
+				authorization = authHeaders[0]
 			}
 
-			users, err := userClient.FetchByIDs(ctx, []string{userID})
+			user, err := Authenticate(ctx, keys, userClient, authorization)
 			if err != nil {
 				return nil, err
 			}
+			ctx = WithUserClaims(ctx, user)
+		}
 
-			user, exists := users[userID]
-			if !exists || user.Email == "" {
-				return nil, status.Error(codes.Unauthenticated, "invalid token")
-			}
+		return handler(ctx, req)
+	}
+}
 
-			ctx = context.WithValue(ctx, userClaimsKey, Claims{
-				ID:      userID,
-				Email:   user.Email,
-				Name:    user.Name,
-				Address: user.Address,
-				Type:    UserType(user.Type),
-			})
+// ForMethods applies interceptor to the listed full method names only, for services
+// whose other methods must stay reachable without a user, such as login.
+func ForMethods(interceptor grpc.UnaryServerInterceptor, methods ...string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(ctx, req)
 		}
+		return interceptor(ctx, req, info, handler)
+	}
+}
 
-		return handler(ctx, req)
+// Authenticate validates a "Bearer <token>" authorization value and resolves the user it was issued to.
+func Authenticate(ctx context.Context, keys *KeySet, userClient UserClient, authorization string) (Claims, error) {
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return Claims{}, status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+	}
+
+	tokenStr := strings.TrimPrefix(authorization, "Bearer ")
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return keys.PublicKey(ctx, kid)
+	}, jwt.WithValidMethods([]string{AlgRS256, AlgEdDSA}))
+	if err != nil || !token.Valid {
+		return Claims{}, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return Claims{}, status.Error(codes.Unauthenticated, "invalid token claims")
 	}
+
+	userID, ok := claims["id"].(string)
+	if !ok || userID == "" {
+		return Claims{}, status.Error(codes.Unauthenticated, "invalid token claims")
+	}
+
+	users, err := userClient.FetchByIDs(ctx, []string{userID})
+	if err != nil {
+		return Claims{}, err
+	}
+
+	user, exists := users[userID]
+	if !exists || user.Email == "" {
+		return Claims{}, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return Claims{
+		ID:            userID,
+		Email:         user.Email,
+		Name:          user.Name,
+		Address:       user.Address,
+		Type:          UserType(user.Type),
+		EmailVerified: user.EmailVerified,
+	}, nil
+}
+
+func WithUserClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, userClaimsKey, claims)
 }
 
 func GetUserClaims(ctx context.Context) (*Claims, error) {
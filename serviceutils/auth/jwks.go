@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	userpb "github.com/situmorangbastian/skyros/proto/user"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// minKeyRefreshInterval bounds how often an unknown kid may trigger a JWKS refetch,
+// so tokens with made-up kids cannot be used to flood userservice.
+const minKeyRefreshInterval = 30 * time.Second
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+type JWKSClient interface {
+	GetJWKS(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*userpb.JsonWebKeySet, error)
+}
+
+// KeySet caches the public keys published by userservice and refetches them when a
+// token references a kid it has not seen yet, which lets keys rotate without restarts.
+type KeySet struct {
+	client      JWKSClient
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	refreshedAt time.Time
+}
+
+func NewKeySet(client JWKSClient) *KeySet {
+	return &KeySet{
+		client: client,
+		keys:   map[string]crypto.PublicKey{},
+	}
+}
+
+// NewStaticKeySet builds a KeySet from an already known JWKS. It never refetches.
+func NewStaticKeySet(jwks *userpb.JsonWebKeySet) (*KeySet, error) {
+	keys, err := parseJWKS(jwks)
+	if err != nil {
+		return nil, err
+	}
+	return &KeySet{keys: keys}, nil
+}
+
+func (ks *KeySet) PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	ks.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	if err := ks.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	key, ok = ks.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+func (ks *KeySet) refresh(ctx context.Context) error {
+	if ks.client == nil {
+		return nil
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if time.Since(ks.refreshedAt) < minKeyRefreshInterval {
+		return nil
+	}
+
+	jwks, err := ks.client.GetJWKS(ctx, &emptypb.Empty{})
+	if err != nil {
+		return err
+	}
+
+	keys, err := parseJWKS(jwks)
+	if err != nil {
+		return err
+	}
+
+	ks.keys = keys
+	ks.refreshedAt = time.Now()
+	return nil
+}
+
+// PublicJWK describes a public key as a JWK identified by kid.
+func PublicJWK(kid string, key crypto.PublicKey) (*userpb.JsonWebKey, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		n := base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+		return &userpb.JsonWebKey{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: AlgRS256,
+			N:   &n,
+			E:   &e,
+		}, nil
+	case ed25519.PublicKey:
+		crv := "Ed25519"
+		x := base64.RawURLEncoding.EncodeToString(k)
+		return &userpb.JsonWebKey{
+			Kty: "OKP",
+			Kid: kid,
+			Use: "sig",
+			Alg: AlgEdDSA,
+			Crv: &crv,
+			X:   &x,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+func parseJWKS(jwks *userpb.JsonWebKeySet) (map[string]crypto.PublicKey, error) {
+	keys := make(map[string]crypto.PublicKey, len(jwks.GetKeys()))
+	for _, jwk := range jwks.GetKeys() {
+		key, err := parseJWK(jwk)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: %w", jwk.GetKid(), err)
+		}
+		keys[jwk.GetKid()] = key
+	}
+	return keys, nil
+}
+
+func parseJWK(jwk *userpb.JsonWebKey) (crypto.PublicKey, error) {
+	switch jwk.GetKty() {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.GetN())
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.GetE())
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "OKP":
+		if jwk.GetCrv() != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %s", jwk.GetCrv())
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.GetX())
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", jwk.GetKty())
+	}
+}
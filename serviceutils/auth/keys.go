@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ReadPrivateKey reads a PEM encoded PKCS#8 RSA or Ed25519 private key.
+func ReadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+// ReadPublicKeys reads every "<name>.pem" PKIX public key in dir, keyed by name.
+func ReadPublicKeys(dir string) (map[string]crypto.PublicKey, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(paths))
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".pem")
+
+		block, err := readPEM(path)
+		if err != nil {
+			return nil, fmt.Errorf("read public key %s: %w", name, err)
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("read public key %s: %w", name, err)
+		}
+
+		switch key.(type) {
+		case *rsa.PublicKey, ed25519.PublicKey:
+			keys[name] = key
+		default:
+			return nil, fmt.Errorf("read public key %s: unsupported key type %T", name, key)
+		}
+	}
+
+	return keys, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	return block, nil
+}
+
+// SigningMethod returns the JWT algorithm used with key.
+func SigningMethod(key crypto.Signer) (jwt.SigningMethod, error) {
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Policy maps a full gRPC method name to the user roles allowed to call it.
+// Methods not listed are not subject to role checks.
+type Policy map[string][]UserType
+
+// PolicyInterceptor enforces policy on the claims set by AuthInterceptor, so it must
+// run after it in the chain.
+func PolicyInterceptor(policy Policy) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		roles, ok := policy[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		user, err := GetUserClaims(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if !user.HasRole(roles...) {
+			return nil, ErrPermissionDenied
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ErrPermissionDenied is returned whenever an authenticated user lacks the role a call requires.
+var ErrPermissionDenied = status.Error(codes.PermissionDenied, "permission denied")
+
+func (c *Claims) HasRole(roles ...UserType) bool {
+	return slices.Contains(roles, c.Type)
+}
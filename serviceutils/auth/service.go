@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceAuthorizationKey carries the calling service's token. It is kept apart from
+// "authorization" so a forwarded end-user token never doubles as a service identity.
+const ServiceAuthorizationKey = "x-service-authorization"
+
+const (
+	serviceTokenTTL = 5 * time.Minute
+	// serviceTokenRenewBefore renews cached tokens early so they never expire in flight.
+	serviceTokenRenewBefore = time.Minute
+)
+
+const serviceIdentityKey contextKey = "serviceIdentity"
+
+// ServiceACL maps a full gRPC method name to the services allowed to call it.
+// Methods not listed are not subject to service authentication.
+type ServiceACL map[string][]string
+
+// ServiceTokenSource mints short-lived tokens that identify this service to the
+// gRPC services it calls. Tokens are cached per audience.
+type ServiceTokenSource struct {
+	name   string
+	key    crypto.Signer
+	mu     sync.Mutex
+	tokens map[string]serviceToken
+}
+
+type serviceToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+func NewServiceTokenSource(name string, key crypto.Signer) *ServiceTokenSource {
+	return &ServiceTokenSource{
+		name:   name,
+		key:    key,
+		tokens: map[string]serviceToken{},
+	}
+}
+
+func (s *ServiceTokenSource) Token(audience string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if token, ok := s.tokens[audience]; ok && now.Add(serviceTokenRenewBefore).Before(token.expiresAt) {
+		return token.value, nil
+	}
+
+	method, err := SigningMethod(s.key)
+	if err != nil {
+		return "", err
+	}
+
+	expiresAt := now.Add(serviceTokenTTL)
+	token := jwt.NewWithClaims(method, jwt.RegisteredClaims{
+		Issuer:    s.name,
+		Audience:  jwt.ClaimStrings{audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	})
+	token.Header["kid"] = s.name
+
+	value, err := token.SignedString(s.key)
+	if err != nil {
+		return "", err
+	}
+
+	s.tokens[audience] = serviceToken{
+		value:     value,
+		expiresAt: expiresAt,
+	}
+	return value, nil
+}
+
+// ServiceClientInterceptor attaches a service token scoped to the called gRPC service.
+func ServiceClientInterceptor(source *ServiceTokenSource) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		token, err := source.Token(grpcServiceName(method))
+		if err != nil {
+			return status.Error(codes.Internal, "failed to create service token")
+		}
+
+		ctx = metadata.AppendToOutgoingContext(ctx, ServiceAuthorizationKey, "Bearer "+token)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// ServiceAuthInterceptor rejects calls to methods in acl unless they carry a valid
+// token signed by one of the trusted services allowed for that method.
+func ServiceAuthInterceptor(trusted map[string]crypto.PublicKey, acl ServiceACL) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		allowed, ok := acl[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(ServiceAuthorizationKey)
+		if len(values) == 0 || !strings.HasPrefix(values[0], "Bearer ") {
+			return nil, status.Error(codes.Unauthenticated, "missing service token")
+		}
+
+		var kid string
+		claims := &jwt.RegisteredClaims{}
+		_, err := jwt.ParseWithClaims(strings.TrimPrefix(values[0], "Bearer "), claims, func(token *jwt.Token) (any, error) {
+			kid, _ = token.Header["kid"].(string)
+			key, ok := trusted[kid]
+			if !ok {
+				return nil, ErrUnknownKey
+			}
+			return key, nil
+		},
+			jwt.WithValidMethods([]string{AlgRS256, AlgEdDSA}),
+			jwt.WithAudience(grpcServiceName(info.FullMethod)),
+			jwt.WithExpirationRequired(),
+		)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid service token")
+		}
+
+		// The token must be signed with the key of the service it claims to be.
+		if kid != claims.Issuer {
+			return nil, status.Error(codes.Unauthenticated, "invalid service token")
+		}
+
+		if !containsService(allowed, claims.Issuer) {
+			return nil, status.Errorf(codes.PermissionDenied, "service %s is not allowed to call %s", claims.Issuer, info.FullMethod)
+		}
+
+		return handler(context.WithValue(ctx, serviceIdentityKey, claims.Issuer), req)
+	}
+}
+
+// GetServiceIdentity returns the name of the service that made an authenticated internal call.
+func GetServiceIdentity(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(serviceIdentityKey).(string)
+	return name, ok
+}
+
+// grpcServiceName turns "/user.UserService/GetUsers" into "user.UserService".
+func grpcServiceName(fullMethod string) string {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
+
+func containsService(services []string, name string) bool {
+	for _, service := range services {
+		if service == name {
+			return true
+		}
+	}
+	return false
+}
@@ -23,10 +23,13 @@ func NewRestErrorHandler() runtime.ErrorHandlerFunc {
 
 		switch st.Code() {
 		case codes.InvalidArgument,
+			codes.FailedPrecondition,
 			codes.AlreadyExists,
+			codes.Aborted,
 			codes.NotFound,
 			codes.Unauthenticated,
-			codes.PermissionDenied:
+			codes.PermissionDenied,
+			codes.ResourceExhausted:
 			runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
 			return
 		case codes.Unavailable:
@@ -0,0 +1,211 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+)
+
+const (
+	// Header is the HTTP header clients send the key in.
+	Header = "Idempotency-Key"
+	// MetadataKey is the gRPC metadata the key is forwarded as.
+	MetadataKey = "idempotency-key"
+
+	// TTL is how long a response is kept for replays.
+	TTL = 24 * time.Hour
+	// Lease is how long a claimed key stays in progress. A key whose request never
+	// completed, e.g. because the process crashed, can be claimed again once it runs out.
+	Lease = 5 * time.Minute
+
+	maxKeyLength = 255
+)
+
+// anonymousScope prefixes the scope of callers without user claims. Their keys are also
+// scoped by the request fingerprint, so one anonymous caller can never replay another's
+// response by guessing its key.
+const anonymousScope = "anonymous:"
+
+// Record is what was stored for a key. Response is nil while the first request is still
+// being handled.
+type Record struct {
+	Fingerprint string
+	Response    *anypb.Any
+}
+
+// Store keeps idempotency records per scope (the calling user) and key.
+type Store interface {
+	// Claim records key for a new request, held for lease. When the key is already in
+	// use, the existing record is returned with claimed set to false.
+	Claim(ctx context.Context, scope, key, fingerprint string, lease time.Duration) (record Record, claimed bool, err error)
+	// Complete stores the response of a claimed key and keeps it for ttl.
+	Complete(ctx context.Context, scope, key string, response *anypb.Any, ttl time.Duration) error
+	// Release forgets a claimed key whose request failed, so the client can retry it.
+	Release(ctx context.Context, scope, key string) error
+}
+
+// Reissuer handles methods whose responses carry credentials that must not be stored,
+// such as RegisterUser. Only a reference to what the first request created is kept, and
+// retries get a freshly issued response for it.
+type Reissuer interface {
+	// Reference returns what to store for the response of a completed request.
+	Reference(ctx context.Context, req any, resp any) (proto.Message, error)
+	// Reissue builds the response of a retry from the stored reference.
+	Reissue(ctx context.Context, reference proto.Message) (any, error)
+}
+
+// HeaderMatcher forwards the Idempotency-Key header to gRPC and falls back to the
+// grpc-gateway defaults for every other header.
+func HeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, Header) {
+		return MetadataKey, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// Interceptor makes the listed methods idempotent for requests carrying an idempotency
+// key: the first response is stored and replayed for retries with the same key. It must
+// run after the auth interceptors so keys are scoped to the calling user.
+func Interceptor(store Store, methods ...string) grpc.UnaryServerInterceptor {
+	enabled := make(map[string]Reissuer, len(methods))
+	for _, method := range methods {
+		enabled[method] = nil
+	}
+	return interceptor(store, enabled)
+}
+
+// ReissuingInterceptor is Interceptor for methods that replay through a Reissuer.
+func ReissuingInterceptor(store Store, reissuers map[string]Reissuer) grpc.UnaryServerInterceptor {
+	return interceptor(store, reissuers)
+}
+
+func interceptor(store Store, enabled map[string]Reissuer) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		reissuer, ok := enabled[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(MetadataKey)
+		if len(values) == 0 || values[0] == "" {
+			return handler(ctx, req)
+		}
+
+		key := values[0]
+		if len(key) > maxKeyLength {
+			return nil, status.Errorf(codes.InvalidArgument, "%s must be at most %d characters", Header, maxKeyLength)
+		}
+
+		log := zerolog.Ctx(ctx)
+
+		fingerprint, err := fingerprint(info.FullMethod, req)
+		if err != nil {
+			log.Error().Err(err).Msg("failed fingerprint request")
+			return nil, status.Error(codes.Internal, "Internal Server Error")
+		}
+
+		scope := anonymousScope + fingerprint
+		if user, err := auth.GetUserClaims(ctx); err == nil {
+			scope = user.ID
+		}
+
+		record, claimed, err := store.Claim(ctx, scope, key, fingerprint, Lease)
+		if err != nil {
+			log.Error().Err(err).Msg("failed Claim idempotency key")
+			return nil, status.Error(codes.Internal, "Internal Server Error")
+		}
+
+		if !claimed {
+			return replay(ctx, record, fingerprint, reissuer)
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if releaseErr := store.Release(ctx, scope, key); releaseErr != nil {
+				log.Error().Err(releaseErr).Msg("failed Release idempotency key")
+			}
+			return nil, err
+		}
+
+		message, ok := resp.(proto.Message)
+		if !ok {
+			return resp, nil
+		}
+
+		if reissuer != nil {
+			message, err = reissuer.Reference(ctx, req, resp)
+		}
+
+		var packed *anypb.Any
+		if err == nil {
+			packed, err = anypb.New(message)
+		}
+		if err == nil {
+			err = store.Complete(ctx, scope, key, packed, TTL)
+		}
+		if err != nil {
+			// The request succeeded, so its response is still returned; a retry will be
+			// told the key is in use until its lease runs out.
+			log.Error().Err(err).Msg("failed Complete idempotency key")
+		}
+
+		return resp, nil
+	}
+}
+
+func replay(ctx context.Context, record Record, fingerprint string, reissuer Reissuer) (any, error) {
+	if record.Fingerprint != fingerprint {
+		return nil, status.Errorf(codes.AlreadyExists, "%s was already used for a different request", Header)
+	}
+
+	if record.Response == nil {
+		return nil, status.Errorf(codes.Aborted, "a request with this %s is still in progress", Header)
+	}
+
+	resp, err := record.Response.UnmarshalNew()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	if reissuer != nil {
+		return reissuer.Reissue(ctx, resp)
+	}
+	return resp, nil
+}
+
+func fingerprint(method string, req any) (string, error) {
+	message, ok := req.(proto.Message)
+	if !ok {
+		return "", status.Error(codes.Internal, "request is not a protobuf message")
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
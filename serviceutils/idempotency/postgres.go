@@ -0,0 +1,145 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// purgeInterval bounds how often expired keys are deleted in bulk. Expired keys are also
+// reclaimed individually when reused, so purging only keeps the table small.
+const purgeInterval = time.Hour
+
+type postgresStore struct {
+	dbpool   *pgxpool.Pool
+	mu       sync.Mutex
+	purgedAt time.Time
+}
+
+// NewPostgresStore keeps records in the idempotency_keys table.
+func NewPostgresStore(dbpool *pgxpool.Pool) Store {
+	return &postgresStore{
+		dbpool: dbpool,
+	}
+}
+
+func (s *postgresStore) Claim(ctx context.Context, scope, key, fingerprint string, lease time.Duration) (Record, bool, error) {
+	timeNow := time.Now().UTC()
+	if err := s.purge(ctx, timeNow); err != nil {
+		return Record{}, false, err
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Insert("idempotency_keys").
+		Columns("scope", "key", "fingerprint", "created_at", "expires_at").
+		Values(scope, key, fingerprint, timeNow, timeNow.Add(lease)).
+		Suffix(`ON CONFLICT (scope, key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			response = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+			WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+			RETURNING scope`).ToSql()
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	var claimedScope string
+	err = s.dbpool.QueryRow(ctx, query, args...).Scan(&claimedScope)
+	if err == nil {
+		return Record{}, true, nil
+	}
+	if err != pgx.ErrNoRows {
+		return Record{}, false, err
+	}
+
+	query, args, err = psql.Select("fingerprint", "response").
+		From("idempotency_keys").
+		Where(sq.Eq{"scope": scope, "key": key}).ToSql()
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	var (
+		record   Record
+		response []byte
+	)
+	err = s.dbpool.QueryRow(ctx, query, args...).Scan(&record.Fingerprint, &response)
+	if err != nil {
+		// The key was released between the two statements; report it as in progress
+		// rather than racing the other request.
+		if err == pgx.ErrNoRows {
+			return Record{Fingerprint: fingerprint}, false, nil
+		}
+		return Record{}, false, err
+	}
+
+	if response != nil {
+		record.Response = &anypb.Any{}
+		if err := proto.Unmarshal(response, record.Response); err != nil {
+			return Record{}, false, err
+		}
+	}
+
+	return record, false, nil
+}
+
+func (s *postgresStore) Complete(ctx context.Context, scope, key string, response *anypb.Any, ttl time.Duration) error {
+	data, err := proto.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	// Until now expires_at was the lease of the claim.
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Update("idempotency_keys").
+		Set("response", data).
+		Set("expires_at", time.Now().UTC().Add(ttl)).
+		Where(sq.Eq{"scope": scope, "key": key}).
+		Where("response IS NULL").ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.dbpool.Exec(ctx, query, args...)
+	return err
+}
+
+func (s *postgresStore) Release(ctx context.Context, scope, key string) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Delete("idempotency_keys").
+		Where(sq.Eq{"scope": scope, "key": key}).
+		Where("response IS NULL").ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.dbpool.Exec(ctx, query, args...)
+	return err
+}
+
+func (s *postgresStore) purge(ctx context.Context, timeNow time.Time) error {
+	s.mu.Lock()
+	if timeNow.Sub(s.purgedAt) < purgeInterval {
+		s.mu.Unlock()
+		return nil
+	}
+	s.purgedAt = timeNow
+	s.mu.Unlock()
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Delete("idempotency_keys").
+		Where(sq.LtOrEq{"expires_at": timeNow}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.dbpool.Exec(ctx, query, args...)
+	return err
+}
@@ -0,0 +1,104 @@
+// Package money represents amounts as integer minor units of a currency, so prices
+// are never rounded, and checks every sum and product for overflow.
+package money
+
+import (
+	"errors"
+	"math"
+
+	commonpb "github.com/situmorangbastian/skyros/proto/common"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	ErrOverflow         = errors.New("money: amount overflows int64")
+)
+
+// Money is an amount in the minor units of a currency, e.g. cents for USD.
+type Money struct {
+	// CurrencyCode is an ISO 4217 code such as "IDR".
+	CurrencyCode string `json:"currency_code"`
+	MinorUnits   int64  `json:"minor_units"`
+}
+
+func New(currencyCode string, minorUnits int64) Money {
+	return Money{
+		CurrencyCode: currencyCode,
+		MinorUnits:   minorUnits,
+	}
+}
+
+// FromProto converts p, treating nil as the zero Money.
+func FromProto(p *commonpb.Money) Money {
+	return New(p.GetCurrencyCode(), p.GetMinorUnits())
+}
+
+func (m Money) Proto() *commonpb.Money {
+	return &commonpb.Money{
+		CurrencyCode: m.CurrencyCode,
+		MinorUnits:   m.MinorUnits,
+	}
+}
+
+// ValidCurrencyCode reports whether code has the shape of an ISO 4217 code: three
+// upper-case letters.
+func ValidCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsZero reports whether m is the zero Money, which has no currency yet.
+func (m Money) IsZero() bool {
+	return m == Money{}
+}
+
+// Add returns m + other. The zero Money takes the currency of the amount added to
+// it, so totals can start from Money{}.
+func (m Money) Add(other Money) (Money, error) {
+	if m.IsZero() {
+		return other, nil
+	}
+	if other.IsZero() {
+		return m, nil
+	}
+	if m.CurrencyCode != other.CurrencyCode {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	a, b := m.MinorUnits, other.MinorUnits
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return Money{}, ErrOverflow
+	}
+
+	return New(m.CurrencyCode, a+b), nil
+}
+
+// Sub returns m - other.
+func (m Money) Sub(other Money) (Money, error) {
+	if other.MinorUnits == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(New(other.CurrencyCode, -other.MinorUnits))
+}
+
+// Multiply returns m * n, e.g. a unit price times a quantity.
+func (m Money) Multiply(n int64) (Money, error) {
+	a := m.MinorUnits
+	if a == 0 || n == 0 {
+		return New(m.CurrencyCode, 0), nil
+	}
+
+	product := a * n
+	if product/n != a || (a == -1 && n == math.MinInt64) || (n == -1 && a == math.MinInt64) {
+		return Money{}, ErrOverflow
+	}
+
+	return New(m.CurrencyCode, product), nil
+}
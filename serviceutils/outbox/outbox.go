@@ -0,0 +1,55 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventspb "github.com/situmorangbastian/skyros/proto/events"
+)
+
+// Execer is satisfied by both pgxpool.Pool and pgx.Tx.
+type Execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// Write records an event about aggregateID in the outbox table. db should be the
+// transaction making the change the event describes, so the change and its event are
+// committed or rolled back together. The relay publishes the event afterwards.
+func Write(ctx context.Context, db Execer, aggregateID string, payload proto.Message) error {
+	packed, err := anypb.New(payload)
+	if err != nil {
+		return err
+	}
+
+	timeNow := time.Now()
+	event := &eventspb.Event{
+		Id:          uuid.New().String(),
+		Type:        string(payload.ProtoReflect().Descriptor().FullName()),
+		AggregateId: aggregateID,
+		OccurredAt:  timestamppb.New(timeNow),
+		Payload:     packed,
+	}
+
+	data, err := proto.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Insert("outbox").
+		Columns("id", "event_type", "aggregate_id", "payload", "created_at").
+		Values(event.GetId(), event.GetType(), aggregateID, data, timeNow).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(ctx, query, args...)
+	return err
+}
@@ -0,0 +1,96 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/protobuf/proto"
+
+	eventspb "github.com/situmorangbastian/skyros/proto/events"
+)
+
+// Publisher delivers domain events to consumers. A Publish error makes the relay retry
+// the event later.
+type Publisher interface {
+	Publish(ctx context.Context, event *eventspb.Event) error
+}
+
+type Handler func(ctx context.Context, event *eventspb.Event) error
+
+// InProcessPublisher hands events to handlers subscribed in the same process. It is
+// meant for tests and for consumers living inside the publishing service.
+type InProcessPublisher struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewInProcessPublisher() *InProcessPublisher {
+	return &InProcessPublisher{
+		handlers: map[string][]Handler{},
+	}
+}
+
+// Subscribe registers handler for events of eventType, e.g. "events.OrderCreated".
+// An empty eventType subscribes to every event.
+func (p *InProcessPublisher) Subscribe(eventType string, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[eventType] = append(p.handlers[eventType], handler)
+}
+
+func (p *InProcessPublisher) Publish(ctx context.Context, event *eventspb.Event) error {
+	p.mu.RLock()
+	handlers := append(append([]Handler{}, p.handlers[""]...), p.handlers[event.GetType()]...)
+	p.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PostgresNotifyChannel is the channel PostgresPublisher notifies with the id of every
+// event it stores.
+const PostgresNotifyChannel = "skyros_events"
+
+type postgresPublisher struct {
+	dbpool *pgxpool.Pool
+}
+
+// NewPostgresPublisher appends events to the events table, which consumers read by id
+// after LISTENing on PostgresNotifyChannel. Republished events are stored once.
+func NewPostgresPublisher(dbpool *pgxpool.Pool) Publisher {
+	return &postgresPublisher{
+		dbpool: dbpool,
+	}
+}
+
+func (p *postgresPublisher) Publish(ctx context.Context, event *eventspb.Event) error {
+	data, err := proto.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Insert("events").
+		Columns("id", "event_type", "aggregate_id", "payload", "occurred_at").
+		Values(event.GetId(), event.GetType(), event.GetAggregateId(), data, event.GetOccurredAt().AsTime()).
+		Suffix("ON CONFLICT (id) DO NOTHING").ToSql()
+	if err != nil {
+		return err
+	}
+
+	// The notification is delivered when the implicit transaction of this statement
+	// commits, so listeners never see an id before its row.
+	query = "WITH stored AS (" + query + " RETURNING id) SELECT pg_notify('" + PostgresNotifyChannel + "', id::text) FROM stored"
+	rows, err := p.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rows.Close()
+	return rows.Err()
+}
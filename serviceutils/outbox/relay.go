@@ -0,0 +1,140 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
+
+	eventspb "github.com/situmorangbastian/skyros/proto/events"
+)
+
+const (
+	relayInterval  = time.Second
+	relayBatchSize = 100
+)
+
+// Relay publishes outbox rows in the order they were written and marks them published.
+// Delivery is at least once: an event is published again if marking it fails, so
+// consumers should deduplicate on Event.id.
+type Relay struct {
+	dbpool    *pgxpool.Pool
+	publisher Publisher
+}
+
+func NewRelay(dbpool *pgxpool.Pool, publisher Publisher) *Relay {
+	return &Relay{
+		dbpool:    dbpool,
+		publisher: publisher,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			published, err := r.PublishPending(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("failed publish outbox events")
+				break
+			}
+			if published < relayBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// PublishPending publishes one batch of unpublished events and returns how many were
+// published. Rows are locked with SKIP LOCKED so several replicas can relay at once.
+func (r *Relay) PublishPending(ctx context.Context) (int, error) {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select("id", "payload").
+		From("outbox").
+		Where("published_at IS NULL").
+		OrderBy("created_at", "id").
+		Limit(relayBatchSize).
+		Suffix("FOR UPDATE SKIP LOCKED").ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	events := make([]*eventspb.Event, 0)
+	for rows.Next() {
+		var (
+			id      string
+			payload []byte
+		)
+		if err := rows.Scan(&id, &payload); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		event := &eventspb.Event{}
+		if err := proto.Unmarshal(payload, event); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// Publishing stops at the first failure so events keep their order; the ones already
+	// published are still marked below.
+	publishedIDs := make([]string, 0, len(events))
+	var publishErr error
+	for _, event := range events {
+		if publishErr = r.publisher.Publish(ctx, event); publishErr != nil {
+			break
+		}
+		publishedIDs = append(publishedIDs, event.GetId())
+	}
+
+	if len(publishedIDs) > 0 {
+		query, args, err := psql.Update("outbox").
+			Set("published_at", time.Now()).
+			Where(sq.Eq{"id": publishedIDs}).ToSql()
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return len(publishedIDs), publishErr
+}
@@ -0,0 +1,80 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails to users.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type logMailer struct {
+	from string
+}
+
+// NewLogMailer returns a Mailer that only writes every message to the request's
+// logger, for local development.
+func NewLogMailer(from string) Mailer {
+	return &logMailer{
+		from: from,
+	}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	zerolog.Ctx(ctx).Info().
+		Str("from", m.from).
+		Str("to", msg.To).
+		Str("subject", msg.Subject).
+		Str("body", msg.Body).
+		Msg("email sent")
+	return nil
+}
+
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+// NewFileMailer returns a Mailer that writes every message to its own .eml file in
+// dir, for local development and manual testing.
+func NewFileMailer(from, dir string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &fileMailer{
+		from: from,
+		dir:  dir,
+	}, nil
+}
+
+func (m *fileMailer) Send(_ context.Context, msg Message) error {
+	timeNow := time.Now().UTC()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", timeNow.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	name := fmt.Sprintf("%s-%s.eml", timeNow.Format("20060102T150405"), uuid.New().String())
+	return os.WriteFile(filepath.Join(m.dir, name), []byte(b.String()), 0o600)
+}
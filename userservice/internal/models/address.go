@@ -0,0 +1,32 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Address is an entry of a user's address book.
+type Address struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"-"`
+	Label      string    `json:"label" validate:"max=64"`
+	Recipient  string    `json:"recipient" validate:"required,max=255"`
+	Street     string    `json:"street" validate:"required,max=255"`
+	City       string    `json:"city" validate:"required,max=128"`
+	PostalCode string    `json:"postal_code" validate:"required,max=32"`
+	Country    string    `json:"country" validate:"required,max=128"`
+	IsDefault  bool      `json:"is_default"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// String renders the address on a single line, e.g. for shipping labels.
+func (a Address) String() string {
+	parts := []string{}
+	for _, part := range []string{a.Recipient, a.Street, strings.TrimSpace(a.PostalCode + " " + a.City), a.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"time"
 )
 
 type User struct {
@@ -10,13 +11,27 @@ type User struct {
 	Name     string   `json:"name" validate:"required"`
 	Data     UserData `json:"data"`
 	Password string   `json:"password" validate:"required"`
+	// DefaultAddress is the default entry of the user's address book, if any.
+	DefaultAddress *Address `json:"-"`
+	// EmailVerifiedAt is nil until the user proves they own Email.
+	EmailVerifiedAt *time.Time `json:"-"`
 }
 
 type UserData struct {
+	// Address is the free-text address given at registration.
 	Address string `json:"address"`
 	Type    string `json:"type"`
 }
 
+// Address returns the user's default address, falling back to the one given at
+// registration.
+func (u User) Address() string {
+	if u.DefaultAddress != nil {
+		return u.DefaultAddress.String()
+	}
+	return u.Data.Address
+}
+
 func (u User) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
 		ID    string `json:"id"`
@@ -29,7 +44,54 @@ func (u User) MarshalJSON() ([]byte, error) {
 	})
 }
 
+type UpdateProfileRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,nefield=CurrentPassword"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
 type UserLoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 }
+
+// RefreshToken is a long-lived credential exchanged for new access tokens. Only the
+// hash of the token is stored; every rotation stays in the family of the original login.
+type RefreshToken struct {
+	ID         string
+	UserID     string
+	FamilyID   string
+	TokenHash  string
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy *string
+	CreatedAt  time.Time
+}
+
+type TokenPurpose string
+
+const (
+	TokenPurposePasswordReset     TokenPurpose = "password_reset"
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+)
+
+// UserToken is a single-use credential mailed to a user to prove they own their email.
+// Only the hash of the token is stored, and issuing a new token for a purpose
+// invalidates the user's earlier ones.
+type UserToken struct {
+	ID        string
+	UserID    string
+	Purpose   TokenPurpose
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
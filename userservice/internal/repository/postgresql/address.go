@@ -0,0 +1,324 @@
+package postgresql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/situmorangbastian/skyros/userservice/internal/models"
+	"github.com/situmorangbastian/skyros/userservice/internal/repository"
+)
+
+var addressColumns = []string{
+	"id",
+	"user_id",
+	"label",
+	"recipient",
+	"street",
+	"city",
+	"postal_code",
+	"country",
+	"is_default",
+	"created_at",
+	"updated_at",
+}
+
+type addressRepo struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewAddressRepository(dbpool *pgxpool.Pool) repository.AddressRepository {
+	return &addressRepo{
+		dbpool: dbpool,
+	}
+}
+
+func (r *addressRepo) StoreAddress(ctx context.Context, address models.Address) (models.Address, error) {
+	timeNow := time.Now().UTC()
+	address.ID = uuid.New().String()
+	address.CreatedAt = timeNow
+	address.UpdatedAt = timeNow
+
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return models.Address{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err = lockUser(ctx, tx, address.UserID); err != nil {
+		return models.Address{}, err
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select("COUNT(*) = 0").
+		From("user_addresses").
+		Where(sq.Eq{"user_id": address.UserID}).ToSql()
+	if err != nil {
+		return models.Address{}, err
+	}
+
+	var first bool
+	if err = tx.QueryRow(ctx, query, args...).Scan(&first); err != nil {
+		return models.Address{}, err
+	}
+	address.IsDefault = address.IsDefault || first
+
+	if address.IsDefault {
+		if err = clearDefaultAddress(ctx, tx, address.UserID, timeNow); err != nil {
+			return models.Address{}, err
+		}
+	}
+
+	query, args, err = psql.Insert("user_addresses").
+		Columns(addressColumns...).
+		Values(
+			address.ID,
+			address.UserID,
+			address.Label,
+			address.Recipient,
+			address.Street,
+			address.City,
+			address.PostalCode,
+			address.Country,
+			address.IsDefault,
+			address.CreatedAt,
+			address.UpdatedAt,
+		).ToSql()
+	if err != nil {
+		return models.Address{}, err
+	}
+
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return models.Address{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return models.Address{}, err
+	}
+	return address, nil
+}
+
+func (r *addressRepo) FetchAddresses(ctx context.Context, userID string) ([]models.Address, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select(addressColumns...).
+		From("user_addresses").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("is_default DESC", "created_at", "id").ToSql()
+	if err != nil {
+		return []models.Address{}, err
+	}
+
+	rows, err := r.dbpool.Query(ctx, query, args...)
+	if err != nil {
+		return []models.Address{}, err
+	}
+	defer rows.Close()
+
+	addresses := []models.Address{}
+	for rows.Next() {
+		address, err := scanAddress(rows)
+		if err != nil {
+			return []models.Address{}, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, rows.Err()
+}
+
+func (r *addressRepo) GetAddress(ctx context.Context, userID string, ID string) (models.Address, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select(addressColumns...).
+		From("user_addresses").
+		Where(sq.Eq{"id": ID, "user_id": userID}).ToSql()
+	if err != nil {
+		return models.Address{}, err
+	}
+
+	address, err := scanAddress(r.dbpool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Address{}, repository.ErrNotFound
+		}
+		return models.Address{}, err
+	}
+	return address, nil
+}
+
+func (r *addressRepo) UpdateAddress(ctx context.Context, address models.Address) (models.Address, error) {
+	timeNow := time.Now().UTC()
+
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return models.Address{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err = lockUser(ctx, tx, address.UserID); err != nil {
+		return models.Address{}, err
+	}
+
+	if address.IsDefault {
+		if err = clearDefaultAddress(ctx, tx, address.UserID, timeNow); err != nil {
+			return models.Address{}, err
+		}
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	update := psql.Update("user_addresses").
+		Set("label", address.Label).
+		Set("recipient", address.Recipient).
+		Set("street", address.Street).
+		Set("city", address.City).
+		Set("postal_code", address.PostalCode).
+		Set("country", address.Country).
+		Set("updated_at", timeNow).
+		Where(sq.Eq{"id": address.ID, "user_id": address.UserID}).
+		Suffix("RETURNING " + strings.Join(addressColumns, ", "))
+	if address.IsDefault {
+		update = update.Set("is_default", true)
+	}
+
+	query, args, err := update.ToSql()
+	if err != nil {
+		return models.Address{}, err
+	}
+
+	result, err := scanAddress(tx.QueryRow(ctx, query, args...))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Address{}, repository.ErrNotFound
+		}
+		return models.Address{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return models.Address{}, err
+	}
+	return result, nil
+}
+
+func (r *addressRepo) DeleteAddress(ctx context.Context, userID string, ID string) error {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err = lockUser(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Delete("user_addresses").
+		Where(sq.Eq{"id": ID, "user_id": userID}).
+		Suffix("RETURNING is_default").ToSql()
+	if err != nil {
+		return err
+	}
+
+	var wasDefault bool
+	if err = tx.QueryRow(ctx, query, args...).Scan(&wasDefault); err != nil {
+		if err == pgx.ErrNoRows {
+			return repository.ErrNotFound
+		}
+		return err
+	}
+
+	if wasDefault {
+		query, args, err = psql.Select("id").
+			From("user_addresses").
+			Where(sq.Eq{"user_id": userID}).
+			OrderBy("created_at", "id").
+			Limit(1).ToSql()
+		if err != nil {
+			return err
+		}
+
+		var oldestID string
+		err = tx.QueryRow(ctx, query, args...).Scan(&oldestID)
+		if err != nil && err != pgx.ErrNoRows {
+			return err
+		}
+
+		if oldestID != "" {
+			query, args, err = psql.Update("user_addresses").
+				Set("is_default", true).
+				Set("updated_at", time.Now().UTC()).
+				Where(sq.Eq{"id": oldestID}).ToSql()
+			if err != nil {
+				return err
+			}
+
+			if _, err = tx.Exec(ctx, query, args...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockUser serializes changes to a user's address book so the default flag always
+// lands on exactly one address.
+func lockUser(ctx context.Context, tx pgx.Tx, userID string) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select("id").
+		From("users").
+		Where(sq.Eq{"id": userID}).
+		Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return err
+	}
+
+	var id string
+	if err = tx.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			return repository.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func clearDefaultAddress(ctx context.Context, tx pgx.Tx, userID string, timeNow time.Time) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Update("user_addresses").
+		Set("is_default", false).
+		Set("updated_at", timeNow).
+		Where(sq.Eq{"user_id": userID, "is_default": true}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, query, args...)
+	return err
+}
+
+func scanAddress(row pgx.Row) (models.Address, error) {
+	address := models.Address{}
+	err := row.Scan(
+		&address.ID,
+		&address.UserID,
+		&address.Label,
+		&address.Recipient,
+		&address.Street,
+		&address.City,
+		&address.PostalCode,
+		&address.Country,
+		&address.IsDefault,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+	)
+	return address, err
+}
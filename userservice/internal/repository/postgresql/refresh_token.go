@@ -0,0 +1,173 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/situmorangbastian/skyros/userservice/internal/models"
+	"github.com/situmorangbastian/skyros/userservice/internal/repository"
+)
+
+type refreshTokenRepo struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewRefreshTokenRepository(dbpool *pgxpool.Pool) repository.RefreshTokenRepository {
+	return &refreshTokenRepo{
+		dbpool: dbpool,
+	}
+}
+
+func (r *refreshTokenRepo) Store(ctx context.Context, token models.RefreshToken) error {
+	return insertRefreshToken(ctx, r.dbpool, token)
+}
+
+func (r *refreshTokenRepo) Rotate(ctx context.Context, tokenHash string, next models.RefreshToken) (models.RefreshToken, error) {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select(
+		"id",
+		"user_id",
+		"family_id",
+		"expires_at",
+		"revoked_at",
+	).
+		From("refresh_tokens").
+		Where(sq.Eq{"token_hash": tokenHash}).
+		Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	current := models.RefreshToken{}
+	err = tx.QueryRow(ctx, query, args...).Scan(
+		&current.ID,
+		&current.UserID,
+		&current.FamilyID,
+		&current.ExpiresAt,
+		&current.RevokedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.RefreshToken{}, repository.ErrNotFound
+		}
+		return models.RefreshToken{}, err
+	}
+
+	timeNow := time.Now().UTC()
+
+	if current.RevokedAt != nil {
+		if err = revokeRefreshTokenFamily(ctx, tx, current.FamilyID, timeNow); err != nil {
+			return models.RefreshToken{}, err
+		}
+		if err = tx.Commit(ctx); err != nil {
+			return models.RefreshToken{}, err
+		}
+		return models.RefreshToken{}, repository.ErrRefreshTokenReused
+	}
+
+	if !current.ExpiresAt.After(timeNow) {
+		return models.RefreshToken{}, repository.ErrRefreshTokenExpired
+	}
+
+	next.UserID = current.UserID
+	next.FamilyID = current.FamilyID
+	if err = insertRefreshToken(ctx, tx, next); err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	query, args, err = psql.Update("refresh_tokens").
+		Set("revoked_at", timeNow).
+		Set("replaced_by", next.ID).
+		Where(sq.Eq{"id": current.ID}).ToSql()
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	return next, nil
+}
+
+func (r *refreshTokenRepo) RevokeFamily(ctx context.Context, tokenHash string) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select("family_id").
+		From("refresh_tokens").
+		Where(sq.Eq{"token_hash": tokenHash}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	var familyID string
+	if err = r.dbpool.QueryRow(ctx, query, args...).Scan(&familyID); err != nil {
+		if err == pgx.ErrNoRows {
+			return repository.ErrNotFound
+		}
+		return err
+	}
+
+	return revokeRefreshTokenFamily(ctx, r.dbpool, familyID, time.Now().UTC())
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+func insertRefreshToken(ctx context.Context, db execer, token models.RefreshToken) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Insert("refresh_tokens").
+		Columns(
+			"id",
+			"user_id",
+			"family_id",
+			"token_hash",
+			"expires_at",
+			"created_at",
+		).
+		Values(
+			token.ID,
+			token.UserID,
+			token.FamilyID,
+			token.TokenHash,
+			token.ExpiresAt,
+			token.CreatedAt,
+		).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(ctx, query, args...)
+	return err
+}
+
+func revokeRefreshTokenFamily(ctx context.Context, db execer, familyID string, revokedAt time.Time) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Update("refresh_tokens").
+		Set("revoked_at", revokedAt).
+		Where(sq.Eq{"family_id": familyID}).
+		Where("revoked_at IS NULL").ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(ctx, query, args...)
+	return err
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	eventspb "github.com/situmorangbastian/skyros/proto/events"
+	"github.com/situmorangbastian/skyros/serviceutils/outbox"
 	"github.com/situmorangbastian/skyros/userservice/internal/models"
 	"github.com/situmorangbastian/skyros/userservice/internal/repository"
 )
@@ -44,10 +46,32 @@ func (r *userRepo) Register(ctx context.Context, user models.User) (models.User,
 		return models.User{}, err
 	}
 
-	_, err = r.dbpool.Exec(ctx, query, args...)
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		return models.User{}, err
 	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	_, err = tx.Exec(ctx, query, args...)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	err = outbox.Write(ctx, tx, user.ID, &eventspb.UserRegistered{
+		UserId: user.ID,
+		Email:  user.Email,
+		Name:   user.Name,
+		Type:   user.Data.Type,
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return models.User{}, err
+	}
 	return user, nil
 }
 
@@ -59,6 +83,7 @@ func (r *userRepo) GetUserByEmail(ctx context.Context, email string) (models.Use
 		"name",
 		"password",
 		"user_data",
+		"email_verified_at",
 	).
 		From("users").
 		Where(sq.Or{
@@ -79,6 +104,7 @@ func (r *userRepo) GetUserByEmail(ctx context.Context, email string) (models.Use
 		&user.Name,
 		&user.Password,
 		&userData,
+		&user.EmailVerifiedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -93,18 +119,67 @@ func (r *userRepo) GetUserByEmail(ctx context.Context, email string) (models.Use
 	return user, nil
 }
 
-func (r *userRepo) FetchUsersByIDs(ctx context.Context, ids []string) (map[string]models.User, error) {
+func (r *userRepo) GetUserByID(ctx context.Context, ID string) (models.User, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	query, args, err := psql.Select(
 		"id",
 		"email",
 		"name",
+		"password",
 		"user_data",
+		"email_verified_at",
 	).
 		From("users").
+		Where(sq.Eq{"id": ID}).ToSql()
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var userData []byte
+
+	user := models.User{}
+	err = r.dbpool.QueryRow(ctx, query, args...).Scan(
+		&user.ID,
+		&user.Email,
+		&user.Name,
+		&user.Password,
+		&userData,
+		&user.EmailVerifiedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.User{}, repository.ErrNotFound
+		}
+		return models.User{}, err
+	}
+	err = json.Unmarshal(userData, &user.Data)
+	if err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+func (r *userRepo) FetchUsersByIDs(ctx context.Context, ids []string) (map[string]models.User, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select(
+		"u.id",
+		"u.email",
+		"u.name",
+		"u.user_data",
+		"u.email_verified_at",
+		"a.id",
+		"a.label",
+		"a.recipient",
+		"a.street",
+		"a.city",
+		"a.postal_code",
+		"a.country",
+	).
+		From("users u").
+		LeftJoin("user_addresses a ON a.user_id = u.id AND a.is_default").
 		Where(sq.Or{
-			sq.Eq{"email": ids},
-			sq.Eq{"id": ids},
+			sq.Eq{"u.email": ids},
+			sq.Eq{"u.id": ids},
 		}).ToSql()
 	if err != nil {
 		return map[string]models.User{}, err
@@ -114,16 +189,26 @@ func (r *userRepo) FetchUsersByIDs(ctx context.Context, ids []string) (map[strin
 	if err != nil {
 		return map[string]models.User{}, err
 	}
+	defer rows.Close()
 
 	users := map[string]models.User{}
 	for rows.Next() {
 		user := models.User{}
 		var userData []byte
+		var addressID, label, recipient, street, city, postalCode, country *string
 		err = rows.Scan(
 			&user.ID,
 			&user.Email,
 			&user.Name,
 			&userData,
+			&user.EmailVerifiedAt,
+			&addressID,
+			&label,
+			&recipient,
+			&street,
+			&city,
+			&postalCode,
+			&country,
 		)
 		if err != nil {
 			return map[string]models.User{}, err
@@ -132,7 +217,88 @@ func (r *userRepo) FetchUsersByIDs(ctx context.Context, ids []string) (map[strin
 		if err != nil {
 			return map[string]models.User{}, err
 		}
+		if addressID != nil {
+			user.DefaultAddress = &models.Address{
+				ID:         *addressID,
+				UserID:     user.ID,
+				Label:      *label,
+				Recipient:  *recipient,
+				Street:     *street,
+				City:       *city,
+				PostalCode: *postalCode,
+				Country:    *country,
+				IsDefault:  true,
+			}
+		}
 		users[user.ID] = user
 	}
-	return users, nil
+	return users, rows.Err()
+}
+
+func (r *userRepo) UpdateProfile(ctx context.Context, user models.User) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Update("users").
+		Set("name", user.Name).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": user.ID}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.dbpool.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *userRepo) UpdatePassword(ctx context.Context, userID string, passwordHash string) error {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err = setPassword(ctx, tx, userID, passwordHash, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// setPassword stores passwordHash and revokes every refresh token of the user, so a
+// changed password signs out all sessions.
+func setPassword(ctx context.Context, tx pgx.Tx, userID string, passwordHash string, timeNow time.Time) error {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Update("users").
+		Set("password", passwordHash).
+		Set("updated_at", timeNow).
+		Where(sq.Eq{"id": userID}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	query, args, err = psql.Update("refresh_tokens").
+		Set("revoked_at", timeNow.UTC()).
+		Where(sq.Eq{"user_id": userID}).
+		Where("revoked_at IS NULL").ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, query, args...)
+	return err
 }
@@ -0,0 +1,183 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/situmorangbastian/skyros/userservice/internal/models"
+	"github.com/situmorangbastian/skyros/userservice/internal/repository"
+)
+
+type userTokenRepo struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewUserTokenRepository(dbpool *pgxpool.Pool) repository.UserTokenRepository {
+	return &userTokenRepo{
+		dbpool: dbpool,
+	}
+}
+
+func (r *userTokenRepo) Store(ctx context.Context, token models.UserToken) error {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Update("user_tokens").
+		Set("used_at", token.CreatedAt).
+		Where(sq.Eq{"user_id": token.UserID, "purpose": token.Purpose}).
+		Where("used_at IS NULL").ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+
+	query, args, err = psql.Insert("user_tokens").
+		Columns(
+			"id",
+			"user_id",
+			"purpose",
+			"token_hash",
+			"expires_at",
+			"created_at",
+		).
+		Values(
+			token.ID,
+			token.UserID,
+			token.Purpose,
+			token.TokenHash,
+			token.ExpiresAt,
+			token.CreatedAt,
+		).ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *userTokenRepo) ResetPassword(ctx context.Context, tokenHash string, passwordHash string) error {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	timeNow := time.Now().UTC()
+	token, err := redeemUserToken(ctx, tx, models.TokenPurposePasswordReset, tokenHash, timeNow)
+	if err != nil {
+		return err
+	}
+
+	if err = setPassword(ctx, tx, token.UserID, passwordHash, timeNow); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *userTokenRepo) VerifyEmail(ctx context.Context, tokenHash string) error {
+	tx, err := r.dbpool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	timeNow := time.Now().UTC()
+	token, err := redeemUserToken(ctx, tx, models.TokenPurposeEmailVerification, tokenHash, timeNow)
+	if err != nil {
+		return err
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Update("users").
+		Set("email_verified_at", timeNow).
+		Set("updated_at", timeNow).
+		Where(sq.Eq{"id": token.UserID}).
+		Where("email_verified_at IS NULL").ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// redeemUserToken marks an unused, unexpired token of purpose as used.
+func redeemUserToken(ctx context.Context, tx pgx.Tx, purpose models.TokenPurpose, tokenHash string, timeNow time.Time) (models.UserToken, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, args, err := psql.Select(
+		"id",
+		"user_id",
+		"expires_at",
+		"used_at",
+	).
+		From("user_tokens").
+		Where(sq.Eq{"token_hash": tokenHash, "purpose": purpose}).
+		Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return models.UserToken{}, err
+	}
+
+	token := models.UserToken{
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+	}
+	err = tx.QueryRow(ctx, query, args...).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.ExpiresAt,
+		&token.UsedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.UserToken{}, repository.ErrNotFound
+		}
+		return models.UserToken{}, err
+	}
+
+	if token.UsedAt != nil {
+		return models.UserToken{}, repository.ErrNotFound
+	}
+
+	if !token.ExpiresAt.After(timeNow) {
+		return models.UserToken{}, repository.ErrTokenExpired
+	}
+
+	query, args, err = psql.Update("user_tokens").
+		Set("used_at", timeNow).
+		Where(sq.Eq{"id": token.ID}).ToSql()
+	if err != nil {
+		return models.UserToken{}, err
+	}
+
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return models.UserToken{}, err
+	}
+
+	token.UsedAt = &timeNow
+	return token, nil
+}
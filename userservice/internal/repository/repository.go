@@ -8,11 +8,61 @@ import (
 )
 
 var (
-	ErrNotFound = errors.New("not found")
+	ErrNotFound            = errors.New("not found")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+	// ErrTokenExpired is returned when redeeming a user token past its expiry.
+	ErrTokenExpired = errors.New("token expired")
 )
 
 type UserRepository interface {
 	Register(ctx context.Context, user models.User) (models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (models.User, error)
+	// GetUserByID returns ErrNotFound when no user has ID.
+	GetUserByID(ctx context.Context, ID string) (models.User, error)
+	// FetchUsersByIDs also loads every user's default address.
 	FetchUsersByIDs(ctx context.Context, ids []string) (map[string]models.User, error)
+	// UpdateProfile returns ErrNotFound when no user has user.ID.
+	UpdateProfile(ctx context.Context, user models.User) error
+	// UpdatePassword stores the new password hash and revokes every refresh token of
+	// the user. It returns ErrNotFound when no user has userID.
+	UpdatePassword(ctx context.Context, userID string, passwordHash string) error
+}
+
+// UserTokenRepository stores the tokens mailed to users and redeems them together with
+// the change they authorize. Redeeming fails with ErrNotFound for unknown, used or
+// superseded tokens and with ErrTokenExpired for expired ones.
+type UserTokenRepository interface {
+	// Store invalidates the user's unused tokens for token.Purpose before saving token.
+	Store(ctx context.Context, token models.UserToken) error
+	// ResetPassword redeems a password reset token, stores the new password hash and
+	// revokes every refresh token of the user.
+	ResetPassword(ctx context.Context, tokenHash string, passwordHash string) error
+	// VerifyEmail redeems an email verification token and marks the user's email verified.
+	VerifyEmail(ctx context.Context, tokenHash string) error
+}
+
+// AddressRepository stores the users' address books. Making an address the default
+// takes the flag away from the user's other addresses.
+type AddressRepository interface {
+	// StoreAddress makes the user's first address the default.
+	StoreAddress(ctx context.Context, address models.Address) (models.Address, error)
+	FetchAddresses(ctx context.Context, userID string) ([]models.Address, error)
+	// GetAddress returns ErrNotFound when the user has no address with ID.
+	GetAddress(ctx context.Context, userID string, ID string) (models.Address, error)
+	// UpdateAddress returns ErrNotFound when the user has no address with address.ID.
+	// Clearing the default flag of the default address is ignored.
+	UpdateAddress(ctx context.Context, address models.Address) (models.Address, error)
+	// DeleteAddress passes the default flag on to the user's oldest remaining address.
+	// It returns ErrNotFound when the user has no address with ID.
+	DeleteAddress(ctx context.Context, userID string, ID string) error
+}
+
+type RefreshTokenRepository interface {
+	Store(ctx context.Context, token models.RefreshToken) error
+	// Rotate revokes the token identified by tokenHash and stores next in its family.
+	// Presenting an already revoked token revokes the whole family and returns
+	// ErrRefreshTokenReused.
+	Rotate(ctx context.Context, tokenHash string, next models.RefreshToken) (models.RefreshToken, error)
+	RevokeFamily(ctx context.Context, tokenHash string) error
 }
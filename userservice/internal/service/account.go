@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	userpb "github.com/situmorangbastian/skyros/proto/user"
+	"github.com/situmorangbastian/skyros/userservice/internal/models"
+)
+
+func (s *service) RequestPasswordReset(ctx context.Context, request *userpb.RequestPasswordResetRequest) (*emptypb.Empty, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.account.RequestPasswordReset").Logger()
+	log.Info().Msg("request received")
+
+	email := strings.TrimSpace(request.GetEmail())
+	if email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if err := s.accountUsecase.RequestPasswordReset(ctx, email); err != nil {
+		log.Error().Err(err).Msg("failed RequestPasswordReset")
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *service) ResetPassword(ctx context.Context, request *userpb.ResetPasswordRequest) (*emptypb.Empty, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.account.ResetPassword").Logger()
+	log.Info().Msg("request received")
+
+	reset := models.ResetPasswordRequest{
+		Token:       request.GetToken(),
+		NewPassword: request.GetNewPassword(),
+	}
+
+	if err := s.validators.Validate(reset); err != nil {
+		return nil, err
+	}
+
+	if err := s.accountUsecase.ResetPassword(ctx, reset); err != nil {
+		log.Error().Err(err).Msg("failed ResetPassword")
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *service) SendVerificationEmail(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.account.SendVerificationEmail").Logger()
+	log.Info().Msg("request received")
+
+	user, err := s.userUsecase.Me(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Me")
+		return nil, err
+	}
+
+	if err := s.accountUsecase.SendVerificationEmail(ctx, user); err != nil {
+		log.Error().Err(err).Msg("failed SendVerificationEmail")
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *service) VerifyEmail(ctx context.Context, request *userpb.VerifyEmailRequest) (*emptypb.Empty, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.account.VerifyEmail").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := s.accountUsecase.VerifyEmail(ctx, request.GetToken()); err != nil {
+		log.Error().Err(err).Msg("failed VerifyEmail")
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	userpb "github.com/situmorangbastian/skyros/proto/user"
+	"github.com/situmorangbastian/skyros/userservice/internal/models"
+)
+
+func (s *service) CreateAddress(ctx context.Context, request *userpb.Address) (*userpb.Address, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.address.CreateAddress").Logger()
+	log.Info().Msg("request received")
+
+	address := toAddressModel(request)
+	if err := s.validators.Validate(address); err != nil {
+		return nil, err
+	}
+
+	res, err := s.addressUsecase.Store(ctx, address)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Store")
+		return nil, err
+	}
+
+	return toAddressProto(res), nil
+}
+
+func (s *service) GetAddresses(ctx context.Context, _ *emptypb.Empty) (*userpb.AddressesResponse, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.address.GetAddresses").Logger()
+	log.Info().Msg("request received")
+
+	res, err := s.addressUsecase.Fetch(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Fetch")
+		return nil, err
+	}
+
+	result := make([]*userpb.Address, 0, len(res))
+	for _, address := range res {
+		result = append(result, toAddressProto(address))
+	}
+
+	return &userpb.AddressesResponse{Result: result}, nil
+}
+
+func (s *service) UpdateAddress(ctx context.Context, request *userpb.Address) (*userpb.Address, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.address.UpdateAddress").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	address := toAddressModel(request)
+	if err := s.validators.Validate(address); err != nil {
+		return nil, err
+	}
+
+	res, err := s.addressUsecase.Update(ctx, address)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Update")
+		return nil, err
+	}
+
+	return toAddressProto(res), nil
+}
+
+func (s *service) DeleteAddress(ctx context.Context, request *userpb.DeleteAddressRequest) (*emptypb.Empty, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.address.DeleteAddress").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if err := s.addressUsecase.Delete(ctx, request.GetId()); err != nil {
+		log.Error().Err(err).Msg("failed Delete")
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *service) GetUserAddress(ctx context.Context, request *userpb.GetUserAddressRequest) (*userpb.Address, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.address.GetUserAddress").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetUserId() == "" || request.GetAddressId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and address_id are required")
+	}
+
+	res, err := s.addressUsecase.GetByUser(ctx, request.GetUserId(), request.GetAddressId())
+	if err != nil {
+		log.Error().Err(err).Msg("failed GetByUser")
+		return nil, err
+	}
+
+	return toAddressProto(res), nil
+}
+
+func toAddressModel(address *userpb.Address) models.Address {
+	return models.Address{
+		ID:         address.GetId(),
+		Label:      strings.TrimSpace(address.GetLabel()),
+		Recipient:  strings.TrimSpace(address.GetRecipient()),
+		Street:     strings.TrimSpace(address.GetStreet()),
+		City:       strings.TrimSpace(address.GetCity()),
+		PostalCode: strings.TrimSpace(address.GetPostalCode()),
+		Country:    strings.TrimSpace(address.GetCountry()),
+		IsDefault:  address.GetIsDefault(),
+	}
+}
+
+func toAddressProto(address models.Address) *userpb.Address {
+	return &userpb.Address{
+		Id:         address.ID,
+		Label:      address.Label,
+		Recipient:  address.Recipient,
+		Street:     address.Street,
+		City:       address.City,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+		IsDefault:  address.IsDefault,
+		Formatted:  address.String(),
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,25 +11,42 @@ import (
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	commonpb "github.com/situmorangbastian/skyros/proto/common"
 	userpb "github.com/situmorangbastian/skyros/proto/user"
 	"github.com/situmorangbastian/skyros/serviceutils"
+	"github.com/situmorangbastian/skyros/serviceutils/idempotency"
 	"github.com/situmorangbastian/skyros/userservice/internal/models"
+	"github.com/situmorangbastian/skyros/userservice/internal/signer"
 	"github.com/situmorangbastian/skyros/userservice/internal/usecase"
 )
 
+const accessTokenTTL = time.Hour
+
 type service struct {
 	userUsecase    usecase.UserUsecase
-	tokenSecretKey string
+	addressUsecase usecase.AddressUsecase
+	accountUsecase usecase.AccountUsecase
+	signer         *signer.Signer
 	validators     serviceutils.CustomValidator
 	logger         zerolog.Logger
 }
 
-func NewUserService(userUsecase usecase.UserUsecase, tokenSecretKey string, validators serviceutils.CustomValidator, logger zerolog.Logger) userpb.UserServiceServer {
+func NewUserService(
+	userUsecase usecase.UserUsecase,
+	addressUsecase usecase.AddressUsecase,
+	accountUsecase usecase.AccountUsecase,
+	signer *signer.Signer,
+	validators serviceutils.CustomValidator,
+	logger zerolog.Logger) userpb.UserServiceServer {
 	return &service{
 		userUsecase:    userUsecase,
-		tokenSecretKey: tokenSecretKey,
+		addressUsecase: addressUsecase,
+		accountUsecase: accountUsecase,
+		signer:         signer,
 		validators:     validators,
 		logger:         logger,
 	}
@@ -65,13 +83,7 @@ func (s *service) GetUsers(ctx context.Context, filter *userpb.UserFilter) (*use
 
 	usersGrpc := map[string]*userpb.User{}
 	for _, user := range users {
-		usersGrpc[user.ID] = &userpb.User{
-			Id:      user.ID,
-			Name:    user.Name,
-			Address: user.Data.Address,
-			Email:   user.Email,
-			Type:    user.Data.Type,
-		}
+		usersGrpc[user.ID] = toUserProto(user)
 	}
 
 	response.Users = usersGrpc
@@ -99,14 +111,22 @@ func (s *service) UserLogin(ctx context.Context, request *userpb.UserLoginReques
 		return nil, err
 	}
 
-	accessToken, err := generateToken(res, s.tokenSecretKey, log)
+	accessToken, err := generateToken(res, s.signer, log)
 	if err != nil {
 		log.Error().Err(err).Msg("failed generateToken")
 		return nil, err
 	}
 
+	refreshToken, err := s.userUsecase.IssueRefreshToken(ctx, res)
+	if err != nil {
+		log.Error().Err(err).Msg("failed IssueRefreshToken")
+		return nil, err
+	}
+
 	return &userpb.UserLoginResponse{
-		AccessToken: accessToken,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(accessTokenTTL.Seconds()),
 	}, nil
 }
 
@@ -142,27 +162,239 @@ func (s *service) RegisterUser(ctx context.Context, request *userpb.RegisterUser
 		return nil, err
 	}
 
-	accessToken, err := generateToken(res, s.tokenSecretKey, log)
+	// The account is created either way; the user can ask for another email later.
+	if err := s.accountUsecase.SendVerificationEmail(ctx, res); err != nil {
+		log.Error().Err(err).Msg("failed SendVerificationEmail")
+	}
+
+	accessToken, err := generateToken(res, s.signer, log)
 	if err != nil {
 		log.Error().Err(err).Msg("failed generateToken")
 		return nil, err
 	}
 
+	refreshToken, err := s.userUsecase.IssueRefreshToken(ctx, res)
+	if err != nil {
+		log.Error().Err(err).Msg("failed IssueRefreshToken")
+		return nil, err
+	}
+
 	return &userpb.RegisterUserResponse{
-		AccessToken: accessToken,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (s *service) RefreshToken(ctx context.Context, request *userpb.RefreshTokenRequest) (*userpb.RefreshTokenResponse, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.user.RefreshToken").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	user, refreshToken, err := s.userUsecase.RefreshToken(ctx, request.GetRefreshToken())
+	if err != nil {
+		log.Error().Err(err).Msg("failed RefreshToken")
+		return nil, err
+	}
+
+	accessToken, err := generateToken(user, s.signer, log)
+	if err != nil {
+		log.Error().Err(err).Msg("failed generateToken")
+		return nil, err
+	}
+
+	return &userpb.RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(accessTokenTTL.Seconds()),
 	}, nil
 }
 
-func generateToken(user models.User, secretKey string, log *zerolog.Logger) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+func (s *service) Logout(ctx context.Context, request *userpb.LogoutRequest) (*emptypb.Empty, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.user.Logout").Logger()
+	log.Info().Msg("request received")
+
+	if request.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	if err := s.userUsecase.Logout(ctx, request.GetRefreshToken()); err != nil {
+		log.Error().Err(err).Msg("failed Logout")
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *service) GetJWKS(ctx context.Context, _ *emptypb.Empty) (*userpb.JsonWebKeySet, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.user.GetJWKS").Logger()
+
+	jwks, err := s.signer.JWKS()
+	if err != nil {
+		log.Error().Err(err).Msg("failed JWKS")
+		return nil, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return jwks, nil
+}
+
+func (s *service) GetMe(ctx context.Context, _ *emptypb.Empty) (*userpb.User, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.user.GetMe").Logger()
+	log.Info().Msg("request received")
+
+	res, err := s.userUsecase.Me(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed Me")
+		return nil, err
+	}
+
+	return toUserProto(res), nil
+}
+
+func (s *service) UpdateProfile(ctx context.Context, request *userpb.UpdateProfileRequest) (*userpb.User, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.user.UpdateProfile").Logger()
+	log.Info().Msg("request received")
+
+	profile := models.UpdateProfileRequest{
+		Name: strings.TrimSpace(request.GetName()),
+	}
+
+	if err := s.validators.Validate(profile); err != nil {
+		return nil, err
+	}
+
+	res, err := s.userUsecase.UpdateProfile(ctx, profile)
+	if err != nil {
+		log.Error().Err(err).Msg("failed UpdateProfile")
+		return nil, err
+	}
+
+	return toUserProto(res), nil
+}
+
+func (s *service) ChangePassword(ctx context.Context, request *userpb.ChangePasswordRequest) (*emptypb.Empty, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.user.ChangePassword").Logger()
+	log.Info().Msg("request received")
+
+	change := models.ChangePasswordRequest{
+		CurrentPassword: request.GetCurrentPassword(),
+		NewPassword:     request.GetNewPassword(),
+	}
+
+	if err := s.validators.Validate(change); err != nil {
+		return nil, err
+	}
+
+	if err := s.userUsecase.ChangePassword(ctx, change); err != nil {
+		log.Error().Err(err).Msg("failed ChangePassword")
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func toUserProto(user models.User) *userpb.User {
+	return &userpb.User{
+		Id:            user.ID,
+		Name:          user.Name,
+		Address:       user.Address(),
+		Email:         user.Email,
+		Type:          user.Data.Type,
+		EmailVerified: user.EmailVerifiedAt != nil,
+	}
+}
+
+type registerUserReissuer struct {
+	userUsecase usecase.UserUsecase
+	signer      *signer.Signer
+}
+
+// NewRegisterUserReissuer lets retries of RegisterUser with the same idempotency key sign
+// in the user the first request created, without storing its tokens.
+func NewRegisterUserReissuer(userUsecase usecase.UserUsecase, signer *signer.Signer) idempotency.Reissuer {
+	return &registerUserReissuer{
+		userUsecase: userUsecase,
+		signer:      signer,
+	}
+}
+
+func (r *registerUserReissuer) Reference(ctx context.Context, req any, resp any) (proto.Message, error) {
+	response, ok := resp.(*userpb.RegisterUserResponse)
+	if !ok {
+		return nil, errors.New("response is not a RegisterUserResponse")
+	}
+
+	// The access token was signed by this service moments ago, so its claims can be read
+	// without verifying it again.
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(response.GetAccessToken(), claims); err != nil {
+		return nil, err
+	}
+
+	userID, ok := claims["id"].(string)
+	if !ok {
+		return nil, errors.New("access token has no user id")
+	}
+
+	return wrapperspb.String(userID), nil
+}
+
+func (r *registerUserReissuer) Reissue(ctx context.Context, reference proto.Message) (any, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.service.user.Reissue").Logger()
+
+	userID, ok := reference.(*wrapperspb.StringValue)
+	if !ok {
+		log.Error().Msg("idempotency reference is not a user id")
+		return nil, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	users, err := r.userUsecase.FetchUsersByIDs(ctx, []string{userID.GetValue()})
+	if err != nil {
+		log.Error().Err(err).Msg("failed FetchUsersByIDs")
+		return nil, err
+	}
+
+	user, ok := users[userID.GetValue()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	accessToken, err := generateToken(user, r.signer, log)
+	if err != nil {
+		log.Error().Err(err).Msg("failed generateToken")
+		return nil, err
+	}
 
-	claims := token.Claims.(jwt.MapClaims)
-	claims["id"] = user.ID
-	claims["exp"] = time.Now().Add(time.Hour * 1).Unix()
+	refreshToken, err := r.userUsecase.IssueRefreshToken(ctx, user)
+	if err != nil {
+		log.Error().Err(err).Msg("failed IssueRefreshToken")
+		return nil, err
+	}
+
+	return &userpb.RegisterUserResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(accessTokenTTL.Seconds()),
+	}, nil
+}
 
-	accessToken, err := token.SignedString([]byte(secretKey))
+func generateToken(user models.User, signer *signer.Signer, log *zerolog.Logger) (string, error) {
+	accessToken, err := signer.Sign(jwt.MapClaims{
+		"id":  user.ID,
+		"exp": time.Now().Add(accessTokenTTL).Unix(),
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("failed token.SignedString")
+		log.Error().Err(err).Msg("failed signer.Sign")
 		return "", status.Error(codes.Internal, "Internal Server Error")
 	}
 
@@ -0,0 +1,91 @@
+package signer
+
+import (
+	"crypto"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	userpb "github.com/situmorangbastian/skyros/proto/user"
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+)
+
+// Signer mints access tokens with the active private key. Every loaded key is
+// published in the JWKS so tokens signed by a retired key still verify until they expire.
+type Signer struct {
+	activeKID string
+	keys      map[string]crypto.Signer
+}
+
+// Load reads every "<kid>.pem" PKCS#8 private key (RSA or Ed25519) in dir. When
+// activeKID is empty the last kid in lexical order signs new tokens, so naming key
+// files by date makes the newest key active.
+func Load(dir, activeKID string) (*Signer, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no signing keys found in %s", dir)
+	}
+
+	sort.Strings(paths)
+
+	s := &Signer{
+		keys: make(map[string]crypto.Signer, len(paths)),
+	}
+	for _, path := range paths {
+		kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+		key, err := auth.ReadPrivateKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("load signing key %s: %w", kid, err)
+		}
+		s.keys[kid] = key
+		s.activeKID = kid
+	}
+
+	if activeKID != "" {
+		if _, ok := s.keys[activeKID]; !ok {
+			return nil, fmt.Errorf("active signing key %s not found in %s", activeKID, dir)
+		}
+		s.activeKID = activeKID
+	}
+
+	return s, nil
+}
+
+func (s *Signer) Sign(claims jwt.Claims) (string, error) {
+	key := s.keys[s.activeKID]
+
+	method, err := auth.SigningMethod(key)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = s.activeKID
+	return token.SignedString(key)
+}
+
+func (s *Signer) JWKS() (*userpb.JsonWebKeySet, error) {
+	kids := make([]string, 0, len(s.keys))
+	for kid := range s.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := &userpb.JsonWebKeySet{}
+	for _, kid := range kids {
+		jwk, err := auth.PublicJWK(kid, s.keys[kid].Public())
+		if err != nil {
+			return nil, err
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks, nil
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/situmorangbastian/skyros/userservice/internal/mailer"
+	"github.com/situmorangbastian/skyros/userservice/internal/models"
+	"github.com/situmorangbastian/skyros/userservice/internal/repository"
+)
+
+// AccountUsecase proves that users own their email through tokens mailed to them.
+type AccountUsecase interface {
+	// RequestPasswordReset succeeds whether or not a user has email, so callers cannot
+	// probe for accounts.
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, request models.ResetPasswordRequest) error
+	SendVerificationEmail(ctx context.Context, user models.User) error
+	VerifyEmail(ctx context.Context, token string) error
+}
+
+const (
+	passwordResetTokenTTL     = time.Hour
+	emailVerificationTokenTTL = 24 * time.Hour
+)
+
+type accountUsecase struct {
+	userRepo      repository.UserRepository
+	userTokenRepo repository.UserTokenRepository
+	mailer        mailer.Mailer
+	publicURL     string
+}
+
+// NewAccountUsecase returns an AccountUsecase whose emails point users at the API
+// served on publicURL.
+func NewAccountUsecase(
+	userRepo repository.UserRepository,
+	userTokenRepo repository.UserTokenRepository,
+	mailer mailer.Mailer,
+	publicURL string) AccountUsecase {
+	return &accountUsecase{
+		userRepo:      userRepo,
+		userTokenRepo: userTokenRepo,
+		mailer:        mailer,
+		publicURL:     publicURL,
+	}
+}
+
+func (u *accountUsecase) RequestPasswordReset(ctx context.Context, email string) error {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.account.RequestPasswordReset").Logger()
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil
+		}
+		log.Error().Err(err).Msg("failed GetUserByEmail")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	rawToken, err := u.issueToken(ctx, user, models.TokenPurposePasswordReset, passwordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	err = u.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to reset the password of your account. If it was you, send this token with your new password to POST %s/v1/users/password/reset within %s:\n\n%s\n\nOtherwise you can ignore this email.\n",
+			user.Name, u.publicURL, passwordResetTokenTTL, rawToken,
+		),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed Send")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return nil
+}
+
+func (u *accountUsecase) ResetPassword(ctx context.Context, request models.ResetPasswordRequest) error {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.account.ResetPassword").Logger()
+
+	hashPassword, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error().Err(err).Msg("failed GenerateFromPassword")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	err = u.userTokenRepo.ResetPassword(ctx, hashToken(request.Token), string(hashPassword))
+	if err != nil {
+		if err == repository.ErrNotFound || err == repository.ErrTokenExpired {
+			return status.Error(codes.InvalidArgument, "invalid or expired token")
+		}
+		log.Error().Err(err).Msg("failed ResetPassword")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return nil
+}
+
+func (u *accountUsecase) SendVerificationEmail(ctx context.Context, user models.User) error {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.account.SendVerificationEmail").Logger()
+
+	if user.EmailVerifiedAt != nil {
+		return status.Error(codes.FailedPrecondition, "email already verified")
+	}
+
+	rawToken, err := u.issueToken(ctx, user, models.TokenPurposeEmailVerification, emailVerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	err = u.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nConfirm that this is your email by sending this token to POST %s/v1/users/email/verify within %s:\n\n%s\n",
+			user.Name, u.publicURL, emailVerificationTokenTTL, rawToken,
+		),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed Send")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return nil
+}
+
+func (u *accountUsecase) VerifyEmail(ctx context.Context, token string) error {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.account.VerifyEmail").Logger()
+
+	err := u.userTokenRepo.VerifyEmail(ctx, hashToken(token))
+	if err != nil {
+		if err == repository.ErrNotFound || err == repository.ErrTokenExpired {
+			return status.Error(codes.InvalidArgument, "invalid or expired token")
+		}
+		log.Error().Err(err).Msg("failed VerifyEmail")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return nil
+}
+
+// issueToken stores a new token of purpose for user and returns the raw token to mail.
+func (u *accountUsecase) issueToken(ctx context.Context, user models.User, purpose models.TokenPurpose, ttl time.Duration) (string, error) {
+	log := zerolog.Ctx(ctx)
+
+	rawToken, err := randomToken()
+	if err != nil {
+		log.Error().Err(err).Msg("failed randomToken")
+		return "", status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	timeNow := time.Now().UTC()
+	err = u.userTokenRepo.Store(ctx, models.UserToken{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashToken(rawToken),
+		ExpiresAt: timeNow.Add(ttl),
+		CreatedAt: timeNow,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed Store user token")
+		return "", status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return rawToken, nil
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+	"github.com/situmorangbastian/skyros/userservice/internal/models"
+	"github.com/situmorangbastian/skyros/userservice/internal/repository"
+)
+
+// AddressUsecase manages the calling user's address book.
+type AddressUsecase interface {
+	Store(ctx context.Context, address models.Address) (models.Address, error)
+	Fetch(ctx context.Context) ([]models.Address, error)
+	Update(ctx context.Context, address models.Address) (models.Address, error)
+	Delete(ctx context.Context, ID string) error
+	// GetByUser returns an address of any user, for other services.
+	GetByUser(ctx context.Context, userID string, ID string) (models.Address, error)
+}
+
+type addressUsecase struct {
+	addressRepo repository.AddressRepository
+}
+
+func NewAddressUsecase(addressRepo repository.AddressRepository) AddressUsecase {
+	return &addressUsecase{
+		addressRepo: addressRepo,
+	}
+}
+
+func (u *addressUsecase) Store(ctx context.Context, address models.Address) (models.Address, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.address.Store").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.Address{}, err
+	}
+	address.UserID = user.ID
+
+	result, err := u.addressRepo.StoreAddress(ctx, address)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return models.Address{}, status.Error(codes.NotFound, "user not found")
+		}
+		log.Error().Err(err).Msg("failed StoreAddress")
+		return models.Address{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return result, nil
+}
+
+func (u *addressUsecase) Fetch(ctx context.Context) ([]models.Address, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.address.Fetch").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return []models.Address{}, err
+	}
+
+	result, err := u.addressRepo.FetchAddresses(ctx, user.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed FetchAddresses")
+		return []models.Address{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return result, nil
+}
+
+func (u *addressUsecase) Update(ctx context.Context, address models.Address) (models.Address, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.address.Update").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.Address{}, err
+	}
+	address.UserID = user.ID
+
+	result, err := u.addressRepo.UpdateAddress(ctx, address)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return models.Address{}, status.Error(codes.NotFound, "address not found")
+		}
+		log.Error().Err(err).Msg("failed UpdateAddress")
+		return models.Address{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return result, nil
+}
+
+func (u *addressUsecase) Delete(ctx context.Context, ID string) error {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.address.Delete").Logger()
+
+	user, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = u.addressRepo.DeleteAddress(ctx, user.ID, ID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return status.Error(codes.NotFound, "address not found")
+		}
+		log.Error().Err(err).Msg("failed DeleteAddress")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return nil
+}
+
+func (u *addressUsecase) GetByUser(ctx context.Context, userID string, ID string) (models.Address, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.address.GetByUser").Logger()
+
+	result, err := u.addressRepo.GetAddress(ctx, userID, ID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return models.Address{}, status.Error(codes.NotFound, "address not found")
+		}
+		log.Error().Err(err).Msg("failed GetAddress")
+		return models.Address{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return result, nil
+}
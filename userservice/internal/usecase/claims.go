@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+)
+
+type userClient struct {
+	userUsecase UserUsecase
+}
+
+// NewUserClient resolves users straight from userUsecase, so userservice can
+// authenticate its own callers without calling itself over gRPC.
+func NewUserClient(userUsecase UserUsecase) auth.UserClient {
+	return &userClient{
+		userUsecase: userUsecase,
+	}
+}
+
+func (c *userClient) FetchByIDs(ctx context.Context, ids []string) (map[string]auth.Claims, error) {
+	users, err := c.userUsecase.FetchUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]auth.Claims, len(users))
+	for _, user := range users {
+		result[user.ID] = auth.Claims{
+			ID:            user.ID,
+			Email:         user.Email,
+			Name:          user.Name,
+			Address:       user.Address(),
+			Type:          auth.UserType(user.Data.Type),
+			EmailVerified: user.EmailVerifiedAt != nil,
+		}
+	}
+	return result, nil
+}
@@ -2,12 +2,19 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
 	"github.com/situmorangbastian/skyros/userservice/internal/models"
 	"github.com/situmorangbastian/skyros/userservice/internal/repository"
 )
@@ -16,17 +23,27 @@ type UserUsecase interface {
 	Login(ctx context.Context, email, password string) (models.User, error)
 	Register(ctx context.Context, user models.User) (models.User, error)
 	FetchUsersByIDs(ctx context.Context, ids []string) (map[string]models.User, error)
+	IssueRefreshToken(ctx context.Context, user models.User) (string, error)
+	RefreshToken(ctx context.Context, refreshToken string) (models.User, string, error)
+	Logout(ctx context.Context, refreshToken string) error
+	Me(ctx context.Context) (models.User, error)
+	UpdateProfile(ctx context.Context, request models.UpdateProfileRequest) (models.User, error)
+	ChangePassword(ctx context.Context, request models.ChangePasswordRequest) error
 }
 
+const refreshTokenTTL = 30 * 24 * time.Hour
+
 type userUsecase struct {
-	userRepo repository.UserRepository
-	logger   zerolog.Logger
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	logger           zerolog.Logger
 }
 
-func NewUserUsecase(userRepo repository.UserRepository, logger zerolog.Logger) UserUsecase {
+func NewUserUsecase(userRepo repository.UserRepository, refreshTokenRepo repository.RefreshTokenRepository, logger zerolog.Logger) UserUsecase {
 	return &userUsecase{
-		userRepo: userRepo,
-		logger:   logger,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		logger:           logger,
 	}
 }
 
@@ -94,3 +111,193 @@ func (u *userUsecase) FetchUsersByIDs(ctx context.Context, ids []string) (map[st
 
 	return users, nil
 }
+
+func (u *userUsecase) IssueRefreshToken(ctx context.Context, user models.User) (string, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.user.IssueRefreshToken").Logger()
+
+	rawToken, token, err := newRefreshToken()
+	if err != nil {
+		log.Error().Err(err).Msg("failed newRefreshToken")
+		return "", status.Error(codes.Internal, "Internal Server Error")
+	}
+	token.UserID = user.ID
+	token.FamilyID = uuid.New().String()
+
+	if err = u.refreshTokenRepo.Store(ctx, token); err != nil {
+		log.Error().Err(err).Msg("failed Store refresh token")
+		return "", status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return rawToken, nil
+}
+
+func (u *userUsecase) RefreshToken(ctx context.Context, refreshToken string) (models.User, string, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.user.RefreshToken").Logger()
+
+	rawToken, next, err := newRefreshToken()
+	if err != nil {
+		log.Error().Err(err).Msg("failed newRefreshToken")
+		return models.User{}, "", status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	next, err = u.refreshTokenRepo.Rotate(ctx, hashToken(refreshToken), next)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound, repository.ErrRefreshTokenExpired:
+			return models.User{}, "", status.Error(codes.Unauthenticated, "invalid refresh token")
+		case repository.ErrRefreshTokenReused:
+			log.Warn().Msg("revoked refresh token reused, token family revoked")
+			return models.User{}, "", status.Error(codes.Unauthenticated, "invalid refresh token")
+		}
+		log.Error().Err(err).Msg("failed Rotate refresh token")
+		return models.User{}, "", status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	users, err := u.userRepo.FetchUsersByIDs(ctx, []string{next.UserID})
+	if err != nil {
+		log.Error().Err(err).Msg("failed FetchUsersByIDs")
+		return models.User{}, "", status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	user, ok := users[next.UserID]
+	if !ok {
+		return models.User{}, "", status.Error(codes.Unauthenticated, "invalid refresh token")
+	}
+
+	return user, rawToken, nil
+}
+
+func (u *userUsecase) Logout(ctx context.Context, refreshToken string) error {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.user.Logout").Logger()
+
+	err := u.refreshTokenRepo.RevokeFamily(ctx, hashToken(refreshToken))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return status.Error(codes.Unauthenticated, "invalid refresh token")
+		}
+		log.Error().Err(err).Msg("failed RevokeFamily")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return nil
+}
+
+// Me returns the calling user.
+func (u *userUsecase) Me(ctx context.Context) (models.User, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.user.Me").Logger()
+
+	claims, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	users, err := u.userRepo.FetchUsersByIDs(ctx, []string{claims.ID})
+	if err != nil {
+		log.Error().Err(err).Msg("failed FetchUsersByIDs")
+		return models.User{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	user, ok := users[claims.ID]
+	if !ok {
+		return models.User{}, status.Error(codes.NotFound, "user not found")
+	}
+
+	return user, nil
+}
+
+func (u *userUsecase) UpdateProfile(ctx context.Context, request models.UpdateProfileRequest) (models.User, error) {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.user.UpdateProfile").Logger()
+
+	claims, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	err = u.userRepo.UpdateProfile(ctx, models.User{
+		ID:   claims.ID,
+		Name: request.Name,
+	})
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return models.User{}, status.Error(codes.NotFound, "user not found")
+		}
+		log.Error().Err(err).Msg("failed UpdateProfile")
+		return models.User{}, status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return u.Me(ctx)
+}
+
+func (u *userUsecase) ChangePassword(ctx context.Context, request models.ChangePasswordRequest) error {
+	log := zerolog.Ctx(ctx)
+	log.With().Str("func", "internal.usecase.user.ChangePassword").Logger()
+
+	claims, err := auth.GetUserClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, claims.ID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return status.Error(codes.NotFound, "user not found")
+		}
+		log.Error().Err(err).Msg("failed GetUserByID")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.CurrentPassword))
+	if err != nil {
+		return status.Error(codes.PermissionDenied, "current password is incorrect")
+	}
+
+	hashPassword, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error().Err(err).Msg("failed GenerateFromPassword")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	if err = u.userRepo.UpdatePassword(ctx, user.ID, string(hashPassword)); err != nil {
+		log.Error().Err(err).Msg("failed UpdatePassword")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return nil
+}
+
+// newRefreshToken returns a random opaque token for the client together with the
+// record to persist, which only keeps its hash.
+func newRefreshToken() (string, models.RefreshToken, error) {
+	rawToken, err := randomToken()
+	if err != nil {
+		return "", models.RefreshToken{}, err
+	}
+
+	timeNow := time.Now().UTC()
+
+	return rawToken, models.RefreshToken{
+		ID:        uuid.New().String(),
+		TokenHash: hashToken(rawToken),
+		ExpiresAt: timeNow.Add(refreshTokenTTL),
+		CreatedAt: timeNow,
+	}, nil
+}
+
+// randomToken returns an opaque token with 256 bits of entropy.
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
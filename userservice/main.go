@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -27,8 +28,13 @@ import (
 
 	userpb "github.com/situmorangbastian/skyros/proto/user"
 	"github.com/situmorangbastian/skyros/serviceutils"
+	"github.com/situmorangbastian/skyros/serviceutils/auth"
+	"github.com/situmorangbastian/skyros/serviceutils/idempotency"
+	"github.com/situmorangbastian/skyros/serviceutils/outbox"
+	"github.com/situmorangbastian/skyros/userservice/internal/mailer"
 	"github.com/situmorangbastian/skyros/userservice/internal/repository/postgresql"
 	"github.com/situmorangbastian/skyros/userservice/internal/service"
+	"github.com/situmorangbastian/skyros/userservice/internal/signer"
 	"github.com/situmorangbastian/skyros/userservice/internal/usecase"
 )
 
@@ -59,7 +65,7 @@ func main() {
 		}).With().Timestamp().Str("service", "userservice").Caller().Logger()
 	}
 
-	required := []string{"DATABASE_URL", "SECRET_KEY", "GRPC_SERVER_PORT", "GRPC_SERVICE_ENDPOINT"}
+	required := []string{"DATABASE_URL", "JWT_KEYS_DIR", "SERVICE_TRUSTED_KEYS_DIR", "GRPC_SERVER_PORT", "GRPC_SERVICE_ENDPOINT"}
 	for _, key := range required {
 		if cfg.GetString(key) == "" {
 			log.Fatal().Str("key", key).Msg("missing required config")
@@ -83,16 +89,91 @@ func main() {
 	}
 	log.Info().Msg("migrations applied successfully")
 
+	tokenSigner, err := signer.Load(cfg.GetString("JWT_KEYS_DIR"), cfg.GetString("JWT_ACTIVE_KID"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load JWT signing keys")
+	}
+
+	trustedServiceKeys, err := auth.ReadPublicKeys(cfg.GetString("SERVICE_TRUSTED_KEYS_DIR"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load trusted service keys")
+	}
+
+	identities, err := auth.NewIdentityVerifier(trustedServiceKeys, "gatewayservice")
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load identity issuers")
+	}
+
+	jwks, err := tokenSigner.JWKS()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to build JWKS")
+	}
+	keys, err := auth.NewStaticKeySet(jwks)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load JWT verification keys")
+	}
+
 	userRepo := postgresql.NewUserRepository(dbpool)
-	userUsecase := usecase.NewUserUsecase(userRepo, log.Logger)
+	refreshTokenRepo := postgresql.NewRefreshTokenRepository(dbpool)
+	userUsecase := usecase.NewUserUsecase(userRepo, refreshTokenRepo, log.Logger)
+	addressUsecase := usecase.NewAddressUsecase(postgresql.NewAddressRepository(dbpool))
+
+	mailFrom := cfg.GetString("MAIL_FROM")
+	if mailFrom == "" {
+		mailFrom = "no-reply@skyros.local"
+	}
+	var userMailer mailer.Mailer
+	switch cfg.GetString("MAILER") {
+	case "", "log":
+		userMailer = mailer.NewLogMailer(mailFrom)
+	case "file":
+		userMailer, err = mailer.NewFileMailer(mailFrom, cfg.GetString("MAILER_DIR"))
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to init file mailer")
+		}
+	default:
+		log.Fatal().Str("mailer", cfg.GetString("MAILER")).Msg("unknown mailer")
+	}
+
+	publicURL := cfg.GetString("PUBLIC_URL")
+	if publicURL == "" {
+		publicURL = "http://localhost:4000"
+	}
+	accountUsecase := usecase.NewAccountUsecase(userRepo, postgresql.NewUserTokenRepository(dbpool), userMailer, strings.TrimSuffix(publicURL, "/"))
+
+	// Only the account methods act on behalf of a user; login, registration and the
+	// like must stay reachable without one.
+	accountMethods := []string{
+		userpb.UserService_GetMe_FullMethodName,
+		userpb.UserService_UpdateProfile_FullMethodName,
+		userpb.UserService_ChangePassword_FullMethodName,
+		userpb.UserService_CreateAddress_FullMethodName,
+		userpb.UserService_GetAddresses_FullMethodName,
+		userpb.UserService_UpdateAddress_FullMethodName,
+		userpb.UserService_DeleteAddress_FullMethodName,
+		userpb.UserService_SendVerificationEmail_FullMethodName,
+	}
+	accountPolicy := auth.Policy{}
+	for _, method := range accountMethods {
+		accountPolicy[method] = []auth.UserType{auth.UserBuyerType, auth.UserSellerType, auth.UserAdminType}
+	}
 
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			serviceutils.CorrelationServerInterceptorWithLogging(),
 			serviceutils.TraceErrors(),
+			auth.ServiceAuthInterceptor(trustedServiceKeys, auth.ServiceACL{
+				userpb.UserService_GetUsers_FullMethodName:       {"gatewayservice", "orderservice", "productservice"},
+				userpb.UserService_GetUserAddress_FullMethodName: {"orderservice"},
+			}),
+			auth.ForMethods(auth.AuthInterceptor(keys, usecase.NewUserClient(userUsecase), identities), accountMethods...),
+			auth.PolicyInterceptor(accountPolicy),
+			idempotency.ReissuingInterceptor(idempotency.NewPostgresStore(dbpool), map[string]idempotency.Reissuer{
+				userpb.UserService_RegisterUser_FullMethodName: service.NewRegisterUserReissuer(userUsecase, tokenSigner),
+			}),
 		),
 	)
-	userService := service.NewUserService(userUsecase, cfg.GetString("SECRET_KEY"), serviceutils.NewCustomValidator(), log.Logger)
+	userService := service.NewUserService(userUsecase, addressUsecase, accountUsecase, tokenSigner, serviceutils.NewCustomValidator(), log.Logger)
 	userpb.RegisterUserServiceServer(grpcServer, userService)
 
 	mux := runtime.NewServeMux(
@@ -105,6 +186,7 @@ func main() {
 			},
 		}),
 		runtime.WithErrorHandler(serviceutils.NewRestErrorHandler()),
+		runtime.WithIncomingHeaderMatcher(idempotency.HeaderMatcher),
 	)
 	if err := userpb.RegisterUserServiceHandlerFromEndpoint(
 		context.Background(),
@@ -122,6 +204,14 @@ func main() {
 
 	wg := sync.WaitGroup{}
 
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		log.Info().Msg("outbox relay starting")
+		outbox.NewRelay(dbpool, outbox.NewPostgresPublisher(dbpool)).Run(relayCtx)
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -158,6 +248,7 @@ func main() {
 		log.Error().Err(err).Msg("failed to shutdown gRPC-Gateway")
 	}
 	grpcServer.GracefulStop()
+	stopRelay()
 	wg.Wait()
 	log.Info().Msg("servers exited")
 }
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS user_addresses (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    label TEXT NOT NULL DEFAULT '',
+    recipient TEXT NOT NULL,
+    street TEXT NOT NULL,
+    city TEXT NOT NULL,
+    postal_code TEXT NOT NULL,
+    country TEXT NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS user_addresses_user_id_idx ON user_addresses (user_id);
+
+-- A user has at most one default address.
+CREATE UNIQUE INDEX IF NOT EXISTS user_addresses_default_idx ON user_addresses (user_id) WHERE is_default;